@@ -0,0 +1,81 @@
+package pagerank
+
+import "math"
+
+// adaptiveRecheckPeriod es cada cuántas iteraciones Adaptive descongela todos
+// los nodos para un paso completo. Un nodo puede parecer convergido antes de
+// que le llegue el cambio de sus vecinos (en grafos de diámetro grande el
+// vector uniforme casi no cambia en las primeras iteraciones).
+const adaptiveRecheckPeriod = 10
+
+// adaptiveTolerance devuelve el cambio relativo por debajo del cual Adaptive
+// congela un nodo. Con Tolerance como umbral, si todos los nodos cambian
+// menos que eso, el cambio L1 del vector también.
+func (opts RankOptionsOf[K]) adaptiveTolerance() float64 {
+	if opts.AdaptiveTolerance > 0 {
+		return opts.AdaptiveTolerance
+	}
+	return opts.Tolerance
+}
+
+// adaptiveState son los nodos congelados de un cálculo con Adaptive. Los
+// motores leen frozen en cada paso; sólo el bucle de iteración lo modifica.
+type adaptiveState struct {
+	frozen    []bool
+	count     int // nodos congelados durante el último paso
+	tolerance float64
+}
+
+func newAdaptiveState(size int, tolerance float64) *adaptiveState {
+	return &adaptiveState{frozen: make([]bool, size), tolerance: tolerance}
+}
+
+// observe recibe el paso de p a v con su veredicto de convergencia y decide
+// si la iteración termina. Sólo un paso sin nodos congelados puede terminarla:
+// con nodos congelados el cambio se subestima, así que en lugar de terminar
+// se descongela todo y se hace un paso completo, igual que cada
+// adaptiveRecheckPeriod iteraciones. Si no, congela los nodos que
+// convergieron en este paso.
+func (a *adaptiveState) observe(iteration int, p, v []float64, converged bool) bool {
+	if a.count > 0 && (converged || iteration%adaptiveRecheckPeriod == 0) {
+		clear(a.frozen)
+		a.count = 0
+		return false
+	}
+	if converged {
+		return true
+	}
+
+	a.count = freezeConverged(a.frozen, p, v, a.tolerance)
+	return false
+}
+
+// adaptiveBound acota la distancia L1 entre p, el resultado del último paso, y el
+// PageRank exacto x*. Cada paso de potencias G contrae las distancias L1 por
+// followingProb, así que |p - x*| <= c/(1-c) |p - previo| después de un paso
+// de potencias, y |p - x*| <= |G p - p| / (1-c) en general. Con Gauss-Seidel y
+// SOR se hace ese paso extra sobre v. Con DanglingDrop el paso no es lineal y
+// la cota es aproximada.
+func adaptiveBound(e engine, solver Solver, followingProb float64, t teleport, c *csr, last Change, p, v []float64) float64 {
+	if solver == PowerIteration {
+		return followingProb / (1.0 - followingProb) * last.L1
+	}
+
+	step := e.step(followingProb, t, c, p, v)
+	return step.L1 / (1.0 - followingProb)
+}
+
+// freezeConverged congela los nodos cuyo cambio relativo entre p y v bajó de
+// tolerance y devuelve cuántos quedan congelados en total
+func freezeConverged(frozen []bool, p, v []float64, tolerance float64) int {
+	count := 0
+	for i := range frozen {
+		if !frozen[i] && math.Abs(v[i]-p[i]) < tolerance*v[i] {
+			frozen[i] = true
+		}
+		if frozen[i] {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,121 @@
+package pagerank
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestFrozenNodesShouldKeepTheirValue(t *testing.T) {
+	pageRank := New()
+	buildTestGraph(pageRank, 1000)
+	c := pageRank.frozenForm()
+	tp := newTeleport[int](nil, nil, c.size(), DanglingUniform)
+	p := make([]float64, c.size())
+	for i := range p {
+		p[i] = float64(i%7 + 1)
+	}
+	normalize(p)
+
+	frozen := make([]bool, c.size())
+	for i := range frozen {
+		frozen[i] = true
+	}
+	step := sequentialStep(0.85, tp, c, p, make([]float64, c.size()), engineOptions{frozen: frozen})
+
+	assert(t, step.L1 < 1e-15)
+}
+
+func TestFreezeConvergedShouldUseTheRelativeChange(t *testing.T) {
+	frozen := []bool{false, false, true, false}
+	p := []float64{0.1, 1e-6, 0.5, 0.3}
+	v := []float64{0.1 + 1e-9, 2e-6, 0.2, 0.3}
+
+	count := freezeConverged(frozen, p, v, 1e-6)
+
+	assertEqual(t, count, 3)
+	assert(t, frozen[0] && !frozen[1] && frozen[2] && frozen[3])
+}
+
+func TestAdaptiveShouldStayWithinTheReportedBound(t *testing.T) {
+	graphs := []struct {
+		name  string
+		build func(pageRank Interface)
+	}{
+		{"hubs", func(pageRank Interface) { hubGraph(pageRank, 3*parallelizationThreshold) }},
+		//nodes far from the borders look converged in the first iterations
+		{"grid", func(pageRank Interface) { shuffledGrid(pageRank, 150) }},
+	}
+
+	for _, graph := range graphs {
+		name, build := graph.name, graph.build
+		reference := New()
+		build(reference)
+		exact, _, _ := reference.RankInto(context.Background(), nil, RankOptions{FollowingProb: 0.85, Tolerance: 1e-14})
+
+		for _, solver := range []Solver{PowerIteration, GaussSeidel} {
+			for _, pageRank := range []Interface{New(), NewConcurrentWithWorkers(4)} {
+				build(pageRank)
+				opts := RankOptions{FollowingProb: 0.85, Tolerance: 1e-8, Solver: solver}
+				full, fullStats, _ := pageRank.RankInto(context.Background(), nil, opts)
+
+				opts.Adaptive = true
+				ranks, stats, err := pageRank.RankInto(context.Background(), nil, opts)
+
+				assertEqual(t, err, nil)
+				assert(t, stats.SkippedUpdates > 0)
+				assert(t, stats.AdaptiveBound > 0 && stats.AdaptiveBound < 1e-6)
+				assertEqual(t, fullStats.SkippedUpdates, 0)
+				assertEqual(t, fullStats.AdaptiveBound, 0.0)
+
+				distance, fullDistance := 0.0, 0.0
+				for i := range exact {
+					distance += math.Abs(ranks[i] - exact[i])
+					fullDistance += math.Abs(full[i] - exact[i])
+				}
+				t.Log(name, solver, "skipped", stats.SkippedUpdates, "bound", stats.AdaptiveBound, "distance", distance, "full", fullDistance)
+				assert(t, distance <= stats.AdaptiveBound)
+			}
+		}
+	}
+}
+
+func TestAdaptiveShouldBeDeterministic(t *testing.T) {
+	const n = 3 * parallelizationThreshold
+	opts := RankOptions{FollowingProb: 0.85, Tolerance: 1e-8, Adaptive: true, Deterministic: true}
+
+	sequential := New()
+	hubGraph(sequential, n)
+	expected, expectedStats, _ := sequential.RankInto(context.Background(), nil, opts)
+
+	concurrent := NewConcurrentWithWorkers(3)
+	hubGraph(concurrent, n)
+	ranks, stats, _ := concurrent.RankInto(context.Background(), nil, opts)
+
+	assertEqual(t, stats.SkippedUpdates, expectedStats.SkippedUpdates)
+	assertEqual(t, stats.AdaptiveBound, expectedStats.AdaptiveBound)
+	for i := range expected {
+		assertEqual(t, ranks[i], expected[i])
+	}
+}
+
+func BenchmarkAdaptive(b *testing.B) {
+	const n = 200000
+	pageRank := New()
+	pageRank.AddEdges(testEdges(n))
+	pageRank.Freeze()
+	dst := make([]float64, n)
+
+	for _, adaptive := range []bool{false, true} {
+		name := "full"
+		if adaptive {
+			name = "adaptive"
+		}
+		b.Run(name, func(b *testing.B) {
+			opts := RankOptions{FollowingProb: 0.85, Tolerance: 1e-8, Adaptive: adaptive}
+			for i := 0; i < b.N; i++ {
+				dst, _, _ = pageRank.RankInto(context.Background(), dst, opts)
+			}
+		})
+	}
+}
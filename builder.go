@@ -0,0 +1,203 @@
+package pagerank
+
+import (
+	"hash/maphash"
+	"math"
+	"runtime"
+	"sync"
+)
+
+// Builder acumula enlaces desde varias goroutines a la vez para volcarlos
+// después en un grafo con MergeInto. Las claves se internan en shards
+// elegidos por hash, cada uno con su propio mutex, así que goroutines que
+// enlazan claves distintas casi nunca compiten por el mismo lock. Cada enlace
+// se guarda en el búfer del shard de su origen.
+type Builder[K comparable] struct {
+	seed   maphash.Seed
+	shards []*builderShard[K]
+}
+
+// builderShard es una partición del Builder. Se reserva por separado para
+// que los mutex de shards vecinos no compartan línea de caché.
+type builderShard[K comparable] struct {
+	mu    sync.Mutex
+	ids   map[K]uint32 // clave -> id local del shard
+	keys  []K          // claves del shard por id local
+	edges []builderEdge
+}
+
+// builderEdge es un enlace con los nodos como (shard, id local)
+type builderEdge struct {
+	fromShard, from uint32
+	toShard, to     uint32
+	weight          float64
+}
+
+// NewBuilder crea un Builder con cuatro shards por CPU
+func NewBuilder[K comparable]() *Builder[K] {
+	return NewBuilderWithShards[K](4 * runtime.NumCPU())
+}
+
+// NewBuilderWithShards crea un Builder con el número de shards dado
+func NewBuilderWithShards[K comparable](numShards int) *Builder[K] {
+	if numShards < 1 {
+		numShards = 1
+	}
+
+	b := &Builder[K]{
+		seed:   maphash.MakeSeed(),
+		shards: make([]*builderShard[K], numShards),
+	}
+	for s := range b.shards {
+		b.shards[s] = &builderShard[K]{ids: make(map[K]uint32)}
+	}
+	return b
+}
+
+// Link agrega un enlace de from a to. Se puede llamar desde varias
+// goroutines a la vez.
+func (b *Builder[K]) Link(from, to K) {
+	b.LinkWeighted(from, to, 1.0)
+}
+
+// LinkWeighted agrega un enlace con peso, igual que Graph.LinkWeighted. Los
+// pesos no positivos o no finitos se ignoran.
+func (b *Builder[K]) LinkWeighted(from, to K, weight float64) {
+	if !(weight > 0) || math.IsInf(weight, 0) {
+		return
+	}
+
+	toShard := b.shardOf(to)
+	shard := b.shards[toShard]
+	shard.mu.Lock()
+	toID := shard.intern(to)
+	shard.mu.Unlock()
+
+	// El origen se interna con el mismo lock con el que se guarda el enlace
+	fromShard := b.shardOf(from)
+	shard = b.shards[fromShard]
+	shard.mu.Lock()
+	fromID := shard.intern(from)
+	shard.edges = append(shard.edges, builderEdge{
+		fromShard: uint32(fromShard),
+		from:      fromID,
+		toShard:   uint32(toShard),
+		to:        toID,
+		weight:    weight,
+	})
+	shard.mu.Unlock()
+}
+
+// MergeInto vuelca los enlaces acumulados en g y vacía el Builder. Equivale
+// a llamar a g.Link / g.LinkWeighted con cada enlace, recorriendo los shards
+// en orden, así que se aplican las GraphOptions de g. No debe llamarse
+// mientras otras goroutines siguen enlazando.
+func (b *Builder[K]) MergeInto(g Graph[K]) {
+	target, ok := g.(interface{ base() *graph[K] })
+	if !ok {
+		b.each(func(from, to K, weight float64) {
+			g.LinkWeighted(from, to, weight)
+		})
+		b.reset()
+		return
+	}
+
+	target.base().merge(b)
+	b.reset()
+}
+
+// Len devuelve el número de enlaces acumulados
+func (b *Builder[K]) Len() int {
+	total := 0
+	for _, shard := range b.shards {
+		shard.mu.Lock()
+		total += len(shard.edges)
+		shard.mu.Unlock()
+	}
+	return total
+}
+
+func (b *Builder[K]) shardOf(key K) int {
+	return int(maphash.Comparable(b.seed, key) % uint64(len(b.shards)))
+}
+
+func (s *builderShard[K]) intern(key K) uint32 {
+	id, ok := s.ids[key]
+	if !ok {
+		id = uint32(len(s.keys))
+		s.ids[key] = id
+		s.keys = append(s.keys, key)
+	}
+	return id
+}
+
+// each recorre los enlaces acumulados en el orden en que MergeInto los vuelca
+func (b *Builder[K]) each(fn func(from, to K, weight float64)) {
+	for _, shard := range b.shards {
+		for _, edge := range shard.edges {
+			fn(b.shards[edge.fromShard].keys[edge.from], b.shards[edge.toShard].keys[edge.to], edge.weight)
+		}
+	}
+}
+
+func (b *Builder[K]) reset() {
+	for s := range b.shards {
+		b.shards[s] = &builderShard[K]{ids: make(map[K]uint32)}
+	}
+}
+
+func (g *graph[K]) base() *graph[K] {
+	return g
+}
+
+// merge agrega los enlaces de b al grafo con las mismas dos pasadas que
+// LoadEdges. Los ids de cada shard se traducen a índices una sola vez: los
+// nodos se crean al aparecer en el primer enlace aceptado, como con Link.
+func (g *graph[K]) merge(b *Builder[K]) {
+	indices := make([][]int, len(b.shards))
+	numberOfLinks := 0
+	for s, shard := range b.shards {
+		indices[s] = make([]int, len(shard.keys))
+		for id := range indices[s] {
+			indices[s][id] = -1
+		}
+		numberOfLinks += len(shard.edges)
+	}
+
+	indexOf := func(shard, id uint32) int {
+		index := indices[shard][id]
+		if index < 0 {
+			index = g.keyAsArrayIndex(b.shards[shard].keys[id])
+			indices[shard][id] = index
+		}
+		return index
+	}
+
+	g.beginMutation()
+	pairs := make([]int, 0, 2*numberOfLinks)
+	weights := make([]float64, 0, numberOfLinks)
+	inDegrees := make([]int, len(g.keyToIndex))
+
+	for _, shard := range b.shards {
+		for _, edge := range shard.edges {
+			if edge.fromShard == edge.toShard && edge.from == edge.to {
+				key := b.shards[edge.fromShard].keys[edge.from]
+				if !g.acceptLink(key, key) {
+					continue
+				}
+			}
+
+			fromAsIndex := indexOf(edge.fromShard, edge.from)
+			toAsIndex := indexOf(edge.toShard, edge.to)
+			pairs = append(pairs, fromAsIndex, toAsIndex)
+			weights = append(weights, edge.weight)
+
+			for len(inDegrees) < len(g.indexToKey) {
+				inDegrees = append(inDegrees, 0)
+			}
+			inDegrees[toAsIndex]++
+		}
+	}
+
+	g.fill(pairs, weights, inDegrees)
+}
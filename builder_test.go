@@ -0,0 +1,116 @@
+package pagerank
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// feedBuilder reparte los enlaces de buildTestGraph entre varias goroutines
+func feedBuilder(b *Builder[int], n, goroutines int) {
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(partition int) {
+			defer wg.Done()
+			for i := partition; i < n; i += goroutines {
+				if i%10 == 0 {
+					continue
+				}
+				for j := 0; j < 4; j++ {
+					b.Link(i, (i*7+j*13+j*j)%n)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestBuilderShouldRankLikeLinkingSequentially(t *testing.T) {
+	const n = 2 * parallelizationThreshold
+
+	expected := New()
+	buildTestGraph(expected, n)
+
+	for _, pageRank := range []Interface{New(), NewConcurrentWithWorkers(4)} {
+		builder := NewBuilderWithShards[int](16)
+		feedBuilder(builder, n, 8)
+		assertEqual(t, builder.Len(), 4*(n-n/10))
+
+		builder.MergeInto(pageRank)
+
+		assertEqual(t, builder.Len(), 0)
+		assertSameRanks(t, pageRank, expected)
+	}
+}
+
+func TestBuilderShouldMergeIntoAnExistingGraph(t *testing.T) {
+	pageRank := NewGraph[string]()
+	pageRank.Link("a", "b")
+
+	builder := NewBuilder[string]()
+	builder.LinkWeighted("b", "c", 3)
+	builder.Link("b", "a")
+	builder.Link("c", "a")
+	builder.LinkWeighted("c", "b", 0)
+	builder.LinkWeighted("a", "c", math.NaN())
+	builder.LinkWeighted("c", "d", math.Inf(1))
+	builder.MergeInto(pageRank)
+
+	expected := NewGraph[string]()
+	expected.Link("a", "b")
+	expected.LinkWeighted("b", "c", 3)
+	expected.Link("b", "a")
+	expected.Link("c", "a")
+
+	actualRanks, _, _ := pageRank.RankContext(context.Background(), RankOptionsOf[string]{FollowingProb: 0.85, Tolerance: 1e-12})
+	expectedRanks, _, _ := expected.RankContext(context.Background(), RankOptionsOf[string]{FollowingProb: 0.85, Tolerance: 1e-12})
+	assertEqual(t, len(actualRanks), 3)
+	for key, rank := range expectedRanks {
+		assert(t, actualRanks[key]-rank < 1e-10 && rank-actualRanks[key] < 1e-10)
+	}
+}
+
+func TestBuilderShouldApplyTheGraphOptions(t *testing.T) {
+	pageRank := NewWithOptions(GraphOptions{Duplicates: DedupeDuplicates, SelfLoops: RejectSelfLoops})
+
+	builder := NewBuilderWithShards[int](4)
+	builder.Link(0, 1)
+	builder.Link(0, 1)
+	builder.Link(1, 0)
+	builder.Link(2, 2)
+	builder.MergeInto(pageRank)
+
+	expected := New()
+	expected.Link(0, 1)
+	expected.Link(1, 0)
+
+	assertSameRanks(t, pageRank, expected)
+	assertConsistent(t, &pageRank.graph)
+	assert(t, errors.Is(pageRank.Err(), ErrSelfLoop))
+	_, ok := pageRank.keyToIndex[2]
+	assert(t, !ok)
+}
+
+func BenchmarkBuilder(b *testing.B) {
+	const n = 100000
+
+	b.Run("link", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			buildTestGraph(New(), n)
+		}
+	})
+
+	for _, goroutines := range []int{1, 4, 8} {
+		b.Run("builder-"+strconv.Itoa(goroutines), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				builder := NewBuilder[int]()
+				feedBuilder(builder, n, goroutines)
+				builder.MergeInto(New())
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package pagerank
+
+import (
+	"iter"
+	"slices"
+)
+
+// LoadHint anticipa el tamaño de una carga con LoadEdges para reservar la
+// memoria de una vez. Los valores son orientativos: 0 equivale a no saberlo y
+// quedarse corto sólo cuesta crecer como con Link.
+type LoadHint struct {
+	Nodes int // Nodos distintos que aparecerán, contando los que ya hay
+	Edges int // Enlaces que producirá la secuencia
+}
+
+// AddEdges agrega los enlaces de edges como si se llamara a Link con cada uno,
+// pero reservando cada lista de adyacencia una sola vez
+func (g *graph[K]) AddEdges(edges [][2]K) {
+	g.LoadEdges(func(yield func(K, K) bool) {
+		for _, edge := range edges {
+			if !yield(edge[0], edge[1]) {
+				return
+			}
+		}
+	}, LoadHint{Edges: len(edges)})
+}
+
+// LoadEdges agrega los enlaces origen -> destino de la secuencia, que se
+// recorre una sola vez. Es equivalente a llamar a Link con cada uno pero se
+// construye en dos pasadas: la primera interna las claves y cuenta los
+// enlaces entrantes de cada nodo, la segunda llena las filas ya reservadas.
+func (g *graph[K]) LoadEdges(edges iter.Seq2[K, K], hint LoadHint) {
+	g.beginMutation()
+
+	if hint.Nodes > len(g.keyToIndex) {
+		if len(g.keyToIndex) == 0 {
+			g.keyToIndex = make(map[K]int, hint.Nodes)
+		}
+		g.indexToKey = slices.Grow(g.indexToKey, hint.Nodes-len(g.indexToKey))
+	}
+
+	pairs := make([]int, 0, 2*max(hint.Edges, 0))
+	inDegrees := make([]int, len(g.keyToIndex), max(hint.Nodes, len(g.keyToIndex)))
+
+	for from, to := range edges {
+		if !g.acceptLink(from, to) {
+			continue
+		}
+
+		fromAsIndex := g.keyAsArrayIndex(from)
+		toAsIndex := g.keyAsArrayIndex(to)
+		pairs = append(pairs, fromAsIndex, toAsIndex)
+
+		for len(inDegrees) < len(g.indexToKey) {
+			inDegrees = append(inDegrees, 0)
+		}
+		inDegrees[toAsIndex]++
+	}
+
+	g.fill(pairs, nil, inDegrees)
+}
+
+// fill es la segunda pasada de una carga: agrega los enlaces pairs (origen,
+// destino, origen, destino...) con los pesos dados (nil = todos 1) sobre
+// filas reservadas según inDegrees. Los índices ya deben existir.
+func (g *graph[K]) fill(pairs []int, weights []float64, inDegrees []int) {
+	if len(pairs) == 0 {
+		return
+	}
+	g.beginMutation()
+
+	size := len(g.keyToIndex)
+	if missingSlots := size - len(g.inLinks); missingSlots > 0 {
+		g.inLinks = append(g.inLinks, make([][]int, missingSlots)...)
+	}
+	if missingSlots := size - len(g.numberOutLinks); missingSlots > 0 {
+		g.numberOutLinks = append(g.numberOutLinks, make([]int, missingSlots)...)
+	}
+
+	if g.inLinkWeights == nil && slices.ContainsFunc(weights, func(weight float64) bool { return weight != 1.0 }) {
+		g.enableWeights()
+	}
+	if g.inLinkWeights != nil {
+		if missingSlots := size - len(g.inLinkWeights); missingSlots > 0 {
+			g.inLinkWeights = append(g.inLinkWeights, make([][]float64, missingSlots)...)
+		}
+		if missingSlots := size - len(g.outLinkWeights); missingSlots > 0 {
+			g.outLinkWeights = append(g.outLinkWeights, make([]float64, missingSlots)...)
+		}
+	}
+
+	for i, inDegree := range inDegrees {
+		if inDegree == 0 {
+			continue
+		}
+		g.inLinks[i] = slices.Grow(g.inLinks[i], inDegree)
+		if g.inLinkWeights != nil {
+			g.inLinkWeights[i] = slices.Grow(g.inLinkWeights[i], inDegree)
+		}
+	}
+
+	for k := 0; k < len(pairs); k += 2 {
+		from, to := pairs[k], pairs[k+1]
+		weight := 1.0
+		if weights != nil {
+			weight = weights[k/2]
+		}
+
+		g.inLinks[to] = append(g.inLinks[to], from)
+		g.numberOutLinks[from]++
+		if g.inLinkWeights != nil {
+			g.inLinkWeights[to] = append(g.inLinkWeights[to], weight)
+			g.outLinkWeights[from] += weight
+		}
+		if g.outLinks != nil {
+			g.updateOutLinks(from, to, weight)
+		}
+	}
+
+	if g.options.Duplicates != KeepDuplicates {
+		g.duplicatesPending = true
+	}
+}
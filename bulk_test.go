@@ -0,0 +1,120 @@
+package pagerank
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestAddEdgesShouldBuildTheSameGraphAsLink(t *testing.T) {
+	const n = 2000
+
+	for _, pageRank := range []Interface{New(), NewConcurrentWithWorkers(4)} {
+		expected := New()
+		buildTestGraph(expected, n)
+
+		pageRank.AddEdges(testEdges(n))
+
+		assertSameRanks(t, pageRank, expected)
+	}
+
+	pageRank := New()
+	pageRank.AddEdges(testEdges(n))
+	expected := New()
+	buildTestGraph(expected, n)
+
+	assertConsistent(t, &pageRank.graph)
+	assert(t, reflect.DeepEqual(pageRank.indexToKey, expected.indexToKey))
+	assert(t, reflect.DeepEqual(pageRank.inLinks, expected.inLinks))
+	assert(t, reflect.DeepEqual(pageRank.numberOutLinks, expected.numberOutLinks))
+}
+
+func TestAddEdgesShouldExtendAnExistingGraph(t *testing.T) {
+	pageRank := New()
+	pageRank.LinkWeighted(0, 1, 3)
+	pageRank.Link(1, 2)
+	pageRank.Freeze()
+	pageRank.AddEdges([][2]int{{2, 0}, {1, 3}, {3, 0}})
+
+	expected := New()
+	expected.LinkWeighted(0, 1, 3)
+	expected.Link(1, 2)
+	expected.Link(2, 0)
+	expected.Link(1, 3)
+	expected.Link(3, 0)
+
+	assertSameRanks(t, pageRank, expected)
+	assertConsistent(t, &pageRank.graph)
+	assertEqual(t, pageRank.outLinkWeights[1], 2.0)
+}
+
+func TestAddEdgesShouldApplyTheGraphOptions(t *testing.T) {
+	pageRank := NewWithOptions(GraphOptions{Duplicates: DedupeDuplicates, SelfLoops: RejectSelfLoops})
+	pageRank.AddEdges([][2]int{{0, 1}, {0, 1}, {1, 0}, {2, 2}})
+
+	expected := New()
+	expected.Link(0, 1)
+	expected.Link(1, 0)
+
+	assertSameRanks(t, pageRank, expected)
+	assert(t, errors.Is(pageRank.Err(), ErrSelfLoop))
+	_, ok := pageRank.keyToIndex[2]
+	assert(t, !ok)
+}
+
+func TestLoadEdgesShouldReadTheSequenceOnce(t *testing.T) {
+	edges := testEdges(500)
+	calls := 0
+	seq := func(yield func(int, int) bool) {
+		calls++
+		for _, edge := range edges {
+			if !yield(edge[0], edge[1]) {
+				return
+			}
+		}
+	}
+
+	pageRank := NewGraph[int]()
+	pageRank.LoadEdges(seq, LoadHint{Nodes: 100, Edges: 10})
+	expected := New()
+	buildTestGraph(expected, 500)
+
+	assertEqual(t, calls, 1)
+	assertSameRanks(t, pageRank, expected)
+	assertConsistent(t, &pageRank.graph)
+}
+
+func BenchmarkLoadEdges(b *testing.B) {
+	const n = 100000
+	edges := testEdges(n)
+
+	b.Run("link", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			pageRank := New()
+			for _, edge := range edges {
+				pageRank.Link(edge[0], edge[1])
+			}
+		}
+	})
+
+	b.Run("add-edges", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			New().AddEdges(edges)
+		}
+	})
+
+	b.Run("load-edges-hint", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			New().LoadEdges(func(yield func(int, int) bool) {
+				for _, edge := range edges {
+					if !yield(edge[0], edge[1]) {
+						return
+					}
+				}
+			}, LoadHint{Nodes: n, Edges: len(edges)})
+		}
+	})
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/dcadenas/pagerank/experimento"
@@ -14,9 +15,71 @@ func main() {
 	dampingFactor := flag.Float64("damping", 0.85, "Factor de amortiguación")
 	tolerance := flag.Float64("tolerance", 0.0001, "Tolerancia para convergencia")
 	seed := flag.Int64("seed", time.Now().UnixNano(), "Semilla para generación de grafos")
+	nombreSolver := flag.String("solver", "power", "Solver: power, gauss-seidel o sor")
+	omega := flag.Float64("omega", 1.0, "Factor de relajación para el solver sor")
+	nombreReparto := flag.String("reparto", "edge-balanced", "Reparto entre workers: edge-balanced, node-balanced o dynamic")
+	nombreKernel := flag.String("kernel", "pull", "Kernel de la iteración de potencias: pull o push")
+	nombreOrden := flag.String("orden", "none", "Renumeración de nodos antes de iterar: none, degree, rcm o gorder")
+	adaptativo := flag.Bool("adaptativo", false, "Congelar los nodos que ya convergieron (PageRank adaptativo)")
+	compararSolvers := flag.Bool("comparar-solvers", false, "Comparar iteraciones y tiempo entre solvers")
+	compararOrdenes := flag.Bool("comparar-ordenes", false, "Comparar el tiempo por iteración entre renumeraciones de nodos")
 	
 	flag.Parse()
 
+	solver, err := experimento.ParsearSolver(*nombreSolver)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	reparto, err := experimento.ParsearReparto(*nombreReparto)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	kernel, err := experimento.ParsearKernel(*nombreKernel)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	orden, err := experimento.ParsearOrden(*nombreOrden)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	if *compararOrdenes {
+		ejecutor := experimento.NuevoEjecutor(experimento.ConfigExperimento{
+			DampingFactor: *dampingFactor,
+			Tolerance:     *tolerance,
+			Solver:        solver,
+			Omega:         *omega,
+			Reparto:       reparto,
+			Kernel:        kernel,
+			Adaptativo:    *adaptativo,
+		}, *seed)
+		resultados := ejecutor.CompararOrdenes()
+		experimento.NuevoAnalizador(resultados).ImprimirComparacionOrdenes(resultados)
+		return
+	}
+
+	if *compararSolvers {
+		ejecutor := experimento.NuevoEjecutor(experimento.ConfigExperimento{
+			DampingFactor: *dampingFactor,
+			Tolerance:     *tolerance,
+			Omega:         *omega,
+			Reparto:       reparto,
+			Kernel:        kernel,
+			Orden:         orden,
+			Adaptativo:    *adaptativo,
+		}, *seed)
+		resultados := ejecutor.CompararSolvers()
+		experimento.NuevoAnalizador(resultados).ImprimirComparacionSolvers(resultados)
+		return
+	}
+
 	fmt.Println("╔═══════════════════════════════════════════════════════════════════════╗")
 	fmt.Println("║     EXPERIMENTO: PageRank Secuencial vs Concurrente                  ║")
 	fmt.Println("║     Diseño de Bloques Totalmente Aleatorizados                       ║")
@@ -28,6 +91,11 @@ func main() {
 	fmt.Printf("  - Damping factor: %.2f\n", *dampingFactor)
 	fmt.Printf("  - Tolerancia: %.4f\n", *tolerance)
 	fmt.Printf("  - Semilla: %d\n", *seed)
+	fmt.Printf("  - Solver: %s\n", solver)
+	fmt.Printf("  - Reparto: %s\n", reparto)
+	fmt.Printf("  - Kernel: %s\n", kernel)
+	fmt.Printf("  - Orden: %s\n", orden)
+	fmt.Printf("  - Adaptativo: %t\n", *adaptativo)
 	fmt.Println()
 	fmt.Println("Diseño Experimental - Progresión 5x:")
 	fmt.Println("  BLOQUE 1 (Pequeño):   20,000 nodos   (baseline)")
@@ -50,6 +118,12 @@ func main() {
 		DampingFactor: *dampingFactor,
 		Tolerance:     *tolerance,
 		NumReplicas:   *numReplicas,
+		Solver:        solver,
+		Omega:         *omega,
+		Reparto:       reparto,
+		Kernel:        kernel,
+		Orden:         orden,
+		Adaptativo:    *adaptativo,
 	}
 
 	// Crear ejecutor
@@ -0,0 +1,197 @@
+package pagerank
+
+import (
+	"container/heap"
+	"math"
+)
+
+// Change resume la diferencia entre dos iterados consecutivos. Los motores
+// calculan todas las normas en una sola pasada (en paralelo en la versión
+// concurrente) y el ConvergenceCriterion elige cuál usar.
+type Change struct {
+	L1       float64 // Suma de |nuevo_i - anterior_i|
+	L2       float64 // Norma euclídea de la diferencia
+	LInf     float64 // Mayor |nuevo_i - anterior_i|
+	Relative float64 // Mayor |nuevo_i - anterior_i| / nuevo_i
+
+	// Vectores comparados, por índice interno. No deben modificarse.
+	Previous, Current []float64
+}
+
+// ConvergenceCriterion decide cuándo se detiene la iteración. Reset se llama
+// al empezar cada cálculo, así que un criterio con estado no debe compartirse
+// entre cálculos simultáneos.
+type ConvergenceCriterion interface {
+	Reset()
+	// Converged devuelve el cambio que se reporta en RankStats y si la
+	// iteración ya convergió
+	Converged(c Change) (change float64, converged bool)
+}
+
+// L1Norm converge cuando la norma L1 del cambio baja del valor dado. Es el
+// criterio por defecto, con Tolerance como valor.
+type L1Norm float64
+
+func (tolerance L1Norm) Reset() {}
+
+func (tolerance L1Norm) Converged(c Change) (float64, bool) {
+	return c.L1, c.L1 <= float64(tolerance)
+}
+
+// L2Norm converge cuando la norma euclídea del cambio baja del valor dado
+type L2Norm float64
+
+func (tolerance L2Norm) Reset() {}
+
+func (tolerance L2Norm) Converged(c Change) (float64, bool) {
+	return c.L2, c.L2 <= float64(tolerance)
+}
+
+// LInfNorm converge cuando ningún nodo cambia más que el valor dado
+type LInfNorm float64
+
+func (tolerance LInfNorm) Reset() {}
+
+func (tolerance LInfNorm) Converged(c Change) (float64, bool) {
+	return c.LInf, c.LInf <= float64(tolerance)
+}
+
+// RelativeChange converge cuando ningún nodo cambia más que esa fracción de
+// su propio rank. A diferencia de las normas absolutas no depende del tamaño
+// del grafo.
+type RelativeChange float64
+
+func (tolerance RelativeChange) Reset() {}
+
+func (tolerance RelativeChange) Converged(c Change) (float64, bool) {
+	return c.Relative, c.Relative <= float64(tolerance)
+}
+
+// TopKStable converge cuando los K nodos de mayor rank, en orden, no cambian
+// durante Iterations iteraciones seguidas (1 si no se indica). Reporta el
+// cambio L1.
+type TopKStable struct {
+	K          int
+	Iterations int
+
+	previous []int
+	stable   int
+}
+
+func (s *TopKStable) Reset() {
+	s.previous = nil
+	s.stable = 0
+}
+
+func (s *TopKStable) Converged(c Change) (float64, bool) {
+	current := topIndices(c.Current, s.K)
+
+	if equalIndices(current, s.previous) {
+		s.stable++
+	} else {
+		s.stable = 0
+	}
+	s.previous = current
+
+	return c.L1, s.stable >= max(s.Iterations, 1)
+}
+
+// topIndices devuelve los k índices de mayor valor en orden descendente (a
+// igual valor, el índice menor primero), usando un montículo de tamaño k
+func topIndices(p []float64, k int) []int {
+	if k > len(p) {
+		k = len(p)
+	}
+	if k <= 0 {
+		return []int{}
+	}
+
+	h := &indexHeap{values: p, indices: make([]int, 0, k)}
+	for i := range p {
+		if len(h.indices) < k {
+			heap.Push(h, i)
+		} else if h.ranksAbove(i, h.indices[0]) {
+			h.indices[0] = i
+			heap.Fix(h, 0)
+		}
+	}
+
+	top := make([]int, len(h.indices))
+	for i := len(top) - 1; i >= 0; i-- {
+		top[i] = heap.Pop(h).(int)
+	}
+	return top
+}
+
+func equalIndices(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// indexHeap es un montículo de índices cuya raíz es el de menor valor
+type indexHeap struct {
+	values  []float64
+	indices []int
+}
+
+// ranksAbove indica si i va antes que j en el orden por rank
+func (h *indexHeap) ranksAbove(i, j int) bool {
+	if h.values[i] != h.values[j] {
+		return h.values[i] > h.values[j]
+	}
+	return i < j
+}
+
+func (h *indexHeap) Len() int           { return len(h.indices) }
+func (h *indexHeap) Less(a, b int) bool { return h.ranksAbove(h.indices[b], h.indices[a]) }
+func (h *indexHeap) Swap(a, b int)      { h.indices[a], h.indices[b] = h.indices[b], h.indices[a] }
+func (h *indexHeap) Push(x any)         { h.indices = append(h.indices, x.(int)) }
+
+func (h *indexHeap) Pop() any {
+	last := h.indices[len(h.indices)-1]
+	h.indices = h.indices[:len(h.indices)-1]
+	return last
+}
+
+// changeAccumulator acumula las normas de Change sobre un rango de nodos
+type changeAccumulator struct {
+	l1, squares, lInf, relative float64
+}
+
+func (a *changeAccumulator) add(previous, current float64) {
+	diff := math.Abs(current - previous)
+	a.l1 += diff
+	a.squares += diff * diff
+	if diff > a.lInf {
+		a.lInf = diff
+	}
+	if current > 0 && diff/current > a.relative {
+		a.relative = diff / current
+	}
+}
+
+// merge suma al acumulador el de otro rango
+func (a *changeAccumulator) merge(other changeAccumulator) {
+	a.l1 += other.l1
+	a.squares += other.squares
+	a.lInf = math.Max(a.lInf, other.lInf)
+	a.relative = math.Max(a.relative, other.relative)
+}
+
+func (a *changeAccumulator) change(p, new_p []float64) Change {
+	return Change{
+		L1:       a.l1,
+		L2:       math.Sqrt(a.squares),
+		LInf:     a.lInf,
+		Relative: a.relative,
+		Previous: p,
+		Current:  new_p,
+	}
+}
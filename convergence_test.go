@@ -0,0 +1,128 @@
+package pagerank
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestConvergenceCriteriaShouldStopBelowTheirTolerance(t *testing.T) {
+	const n = 2 * parallelizationThreshold
+
+	criteria := []struct {
+		criterion ConvergenceCriterion
+		tolerance float64
+	}{
+		{L1Norm(1e-8), 1e-8},
+		{L2Norm(1e-9), 1e-9},
+		{LInfNorm(1e-10), 1e-10},
+		{RelativeChange(1e-6), 1e-6},
+	}
+
+	for _, pageRank := range []Interface{New(), NewConcurrentWithWorkers(4)} {
+		buildTestGraph(pageRank, n)
+
+		expected, _, _ := pageRank.RankContext(context.Background(), RankOptions{
+			FollowingProb: 0.85,
+			Tolerance:     1e-12,
+		})
+
+		for _, c := range criteria {
+			ranks, stats, err := pageRank.RankContext(context.Background(), RankOptions{
+				FollowingProb: 0.85,
+				Convergence:   c.criterion,
+			})
+
+			assertEqual(t, err, nil)
+			assertSumsToOne(t, ranks)
+			assert(t, stats.Residual <= c.tolerance)
+			assert(t, stats.Residuals[len(stats.Residuals)-2] > c.tolerance)
+			for label, rank := range expected {
+				if math.Abs(rank-ranks[label]) > 1e-6 {
+					t.Error(c.criterion, "rank for", label, "should be", rank, "but was", ranks[label])
+				}
+			}
+		}
+	}
+}
+
+func TestTopKStableShouldStopOnceTheOrderSettles(t *testing.T) {
+	const n = 2000
+
+	pageRank := New()
+	buildTestGraph(pageRank, n)
+
+	_, strictStats, _ := pageRank.RankContext(context.Background(), RankOptions{
+		FollowingProb: 0.85,
+		Tolerance:     1e-12,
+	})
+	expected := topIndices(pageRank.lastRanks, 10)
+
+	_, stats, err := pageRank.RankContext(context.Background(), RankOptions{
+		FollowingProb: 0.85,
+		Convergence:   &TopKStable{K: 10, Iterations: 3},
+	})
+
+	assertEqual(t, err, nil)
+	assert(t, stats.Iterations < strictStats.Iterations)
+	assert(t, equalIndices(topIndices(pageRank.lastRanks, 10), expected))
+}
+
+func TestTopKStableShouldResetBetweenRanks(t *testing.T) {
+	pageRank := New()
+	wikipediaExample(pageRank)
+	criterion := &TopKStable{K: 3, Iterations: 2}
+
+	_, first, _ := pageRank.RankContext(context.Background(), RankOptions{FollowingProb: 0.85, Convergence: criterion})
+	_, second, _ := pageRank.RankContext(context.Background(), RankOptions{FollowingProb: 0.85, Convergence: criterion})
+
+	assertEqual(t, second.Iterations, first.Iterations)
+}
+
+func TestTopKStableShouldWaitOneIterationByDefault(t *testing.T) {
+	pageRank := New()
+	wikipediaExample(pageRank)
+
+	_, stats, err := pageRank.RankContext(context.Background(), RankOptions{FollowingProb: 0.85, Convergence: &TopKStable{K: 3}})
+	_, expected, _ := pageRank.RankContext(context.Background(), RankOptions{FollowingProb: 0.85, Convergence: &TopKStable{K: 3, Iterations: 1}})
+
+	assertEqual(t, err, nil)
+	assert(t, stats.Iterations > 1)
+	assertEqual(t, stats.Iterations, expected.Iterations)
+}
+
+func TestTopIndicesShouldSortByRankThenIndex(t *testing.T) {
+	p := []float64{0.1, 0.3, 0.05, 0.3, 0.25}
+
+	assert(t, equalIndices(topIndices(p, 3), []int{1, 3, 4}))
+	assert(t, equalIndices(topIndices(p, 10), []int{1, 3, 4, 0, 2}))
+	assertEqual(t, len(topIndices(p, 0)), 0)
+}
+
+func TestConcurrentChangeShouldMatchTheSequentialOne(t *testing.T) {
+	const n = 4 * parallelizationThreshold
+	pageRank := NewConcurrentWithWorkers(4)
+	buildTestGraph(pageRank, n)
+
+	c := pageRank.frozenForm()
+	tp := newTeleport[int](nil, nil, c.size(), DanglingUniform)
+	p := make([]float64, n)
+	for i := range p {
+		p[i] = float64(i%97+1) / n
+	}
+	normalize(p)
+
+	expected := sequentialStep(0.85, tp, c, p, make([]float64, n), engineOptions{})
+
+	stop := pageRank.start(c, engineOptions{})
+	defer stop(&RankStats{})
+	actual := pageRank.step(0.85, tp, c, p, make([]float64, n))
+
+	assert(t, math.Abs(actual.L1-expected.L1) < 1e-9)
+	assert(t, math.Abs(actual.L2-expected.L2) < 1e-12)
+	assert(t, math.Abs(actual.LInf-expected.LInf) < 1e-15)
+	assert(t, math.Abs(actual.Relative-expected.Relative) < 1e-12)
+	for i := range p {
+		assert(t, math.Abs(actual.Current[i]-expected.Current[i]) < 1e-15)
+	}
+}
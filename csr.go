@@ -0,0 +1,176 @@
+package pagerank
+
+// csr es la forma congelada del grafo: los enlaces entrantes de todos los
+// nodos en un único arreglo (compressed sparse row). Los enlaces que llegan al
+// nodo i están en inLinks[offsets[i]:offsets[i+1]].
+type csr struct {
+	offsets         []int
+	inLinks         []uint32
+	weights         []float64 // nil sin pesos, paralelo a inLinks
+	inverseOutLinks []float64 // 1/(peso saliente), 0 en los nodos colgantes
+	danglingNodes   []uint32
+
+	// Adyacencia saliente del kernel push, nil hasta que se usa: los
+	// destinos del nodo i están en outLinks[outOffsets[i]:outOffsets[i+1]]
+	outOffsets []int
+	outLinks   []uint32
+	outWeights []float64 // nil sin pesos, paralelo a outLinks
+}
+
+func (c *csr) size() int {
+	return len(c.offsets) - 1
+}
+
+// buildCSR convierte las listas de adyacencia del constructor en la forma CSR
+func (g *graph[K]) buildCSR() *csr {
+	size := len(g.inLinks)
+	numberOfLinks := 0
+	for _, inLinksForI := range g.inLinks {
+		numberOfLinks += len(inLinksForI)
+	}
+
+	c := &csr{
+		offsets:         make([]int, size+1),
+		inLinks:         make([]uint32, 0, numberOfLinks),
+		inverseOutLinks: make([]float64, size),
+	}
+	if g.inLinkWeights != nil {
+		c.weights = make([]float64, 0, numberOfLinks)
+	}
+
+	for i, inLinksForI := range g.inLinks {
+		for _, from := range inLinksForI {
+			c.inLinks = append(c.inLinks, uint32(from))
+		}
+		if c.weights != nil {
+			c.weights = append(c.weights, g.inLinkWeights[i]...)
+		}
+		c.offsets[i+1] = len(c.inLinks)
+	}
+
+	for i, outLinks := range g.numberOutLinks {
+		if outLinks == 0 {
+			c.danglingNodes = append(c.danglingNodes, uint32(i))
+			continue
+		}
+		if g.outLinkWeights != nil {
+			c.inverseOutLinks[i] = 1.0 / g.outLinkWeights[i]
+		} else {
+			c.inverseOutLinks[i] = 1.0 / float64(outLinks)
+		}
+	}
+
+	return c
+}
+
+// frozenForm devuelve la forma CSR con la que iteran los motores,
+// construyéndola si el grafo cambió desde la última vez
+func (g *graph[K]) frozenForm() *csr {
+	if g.csr == nil {
+		g.compactDuplicates()
+		g.csr = g.buildCSR()
+	}
+	return g.csr
+}
+
+// Freeze convierte el grafo a su forma CSR compacta y libera las listas de
+// adyacencia del constructor. Rank funciona igual sobre un grafo congelado;
+// cualquier modificación posterior lo descongela automáticamente.
+func (g *graph[K]) Freeze() {
+	if g.frozen {
+		return
+	}
+
+	g.frozenForm()
+	g.inLinks = nil
+	g.inLinkWeights = nil
+	g.numberOutLinks = nil
+	g.outLinkWeights = nil
+	g.outLinks = nil
+	g.frozen = true
+}
+
+// thaw reconstruye las listas de adyacencia a partir de la forma CSR
+func (g *graph[K]) thaw() {
+	c := g.csr
+	size := c.size()
+
+	g.inLinks = make([][]int, size)
+	g.numberOutLinks = make([]int, size)
+	if c.weights != nil {
+		g.inLinkWeights = make([][]float64, size)
+		g.outLinkWeights = make([]float64, size)
+	}
+
+	for i := 0; i < size; i++ {
+		start, end := c.offsets[i], c.offsets[i+1]
+
+		inLinks := make([]int, end-start)
+		for j, from := range c.inLinks[start:end] {
+			inLinks[j] = int(from)
+			g.numberOutLinks[from]++
+		}
+		g.inLinks[i] = inLinks
+
+		if c.weights != nil {
+			weights := append([]float64(nil), c.weights[start:end]...)
+			for j, from := range c.inLinks[start:end] {
+				g.outLinkWeights[from] += weights[j]
+			}
+			g.inLinkWeights[i] = weights
+		}
+	}
+
+	g.frozen = false
+}
+
+// beginMutation prepara el grafo para una modificación: lo descongela si hace
+// falta y descarta la forma CSR y el resultado previo, que dejan de valer
+func (g *graph[K]) beginMutation() {
+	if g.frozen {
+		g.thaw()
+	}
+	g.csr = nil
+	g.lastRanksStale = true
+}
+
+// inLinksSum suma el rank que recibe el nodo i de sus enlaces entrantes
+func (c *csr) inLinksSum(i int, p []float64) float64 {
+	ksum := 0.0
+	start, end := c.offsets[i], c.offsets[i+1]
+
+	if c.weights == nil {
+		for _, index := range c.inLinks[start:end] {
+			ksum += p[index] * c.inverseOutLinks[index]
+		}
+		return ksum
+	}
+
+	weights := c.weights[start:end]
+	for j, index := range c.inLinks[start:end] {
+		ksum += p[index] * weights[j] * c.inverseOutLinks[index]
+	}
+
+	return ksum
+}
+
+// inLinksSumBlock es inLinksSum para Gauss-Seidel por bloques: los orígenes
+// dentro de [start, end) se leen de v (valores del barrido actual) y el resto
+// de p, que nadie escribe durante el barrido.
+func (c *csr) inLinksSumBlock(i int, p, v []float64, start, end int, compensated bool) float64 {
+	ksum := accumulator{compensated: compensated}
+	first, last := c.offsets[i], c.offsets[i+1]
+
+	for j, index := range c.inLinks[first:last] {
+		x := p[index]
+		if int(index) >= start && int(index) < end {
+			x = v[index]
+		}
+		if c.weights != nil {
+			x *= c.weights[first+j]
+		}
+		ksum.add(x * c.inverseOutLinks[index])
+	}
+
+	return ksum.value()
+}
@@ -0,0 +1,76 @@
+package pagerank
+
+import (
+	"testing"
+)
+
+func TestFrozenGraphShouldRankLikeTheMutableOne(t *testing.T) {
+	for _, frozen := range []Interface{New(), NewConcurrentWithWorkers(4)} {
+		wikipediaExample(frozen)
+		frozen.Freeze()
+
+		expected := New()
+		wikipediaExample(expected)
+
+		assertSameRanks(t, frozen, expected)
+	}
+}
+
+func TestFreezeShouldReleaseTheAdjacencyLists(t *testing.T) {
+	pageRank := New()
+	pageRank.LinkWeighted(0, 1, 2)
+	pageRank.Link(1, 2)
+	pageRank.Freeze()
+
+	assert(t, pageRank.inLinks == nil)
+	assert(t, pageRank.inLinkWeights == nil)
+	assert(t, pageRank.numberOutLinks == nil)
+	assertEqual(t, pageRank.csr.size(), 3)
+	assertEqual(t, len(pageRank.csr.inLinks), 2)
+	assertEqual(t, len(pageRank.csr.danglingNodes), 1)
+	assertEqual(t, pageRank.csr.inverseOutLinks[0], 0.5)
+}
+
+func TestFrozenGraphShouldThawWhenModified(t *testing.T) {
+	pageRank := New()
+	pageRank.LinkWeighted(0, 1, 3)
+	pageRank.LinkWeighted(1, 2, 1)
+	pageRank.LinkWeighted(2, 0, 2)
+	pageRank.LinkWeighted(2, 1, 1)
+	pageRank.Freeze()
+	pageRank.Link(1, 3)
+	pageRank.Freeze()
+	pageRank.Unlink(2, 1)
+	pageRank.Freeze()
+	pageRank.RemoveNode(3)
+
+	expected := New()
+	expected.LinkWeighted(0, 1, 3)
+	expected.LinkWeighted(1, 2, 1)
+	expected.LinkWeighted(2, 0, 2)
+
+	assertConsistent(t, &pageRank.graph)
+	assertSameRanks(t, pageRank, expected)
+}
+
+func TestRankShouldNotReuseAStaleFrozenForm(t *testing.T) {
+	pageRank := New()
+	pageRank.Link(0, 1)
+	assertRank(t, pageRank, map[int]float64{0: 35.1, 1: 64.9})
+	pageRank.Link(1, 2)
+	assertRank(t, pageRank, map[int]float64{0: 18.4, 1: 34.1, 2: 47.4})
+}
+
+func TestApplyLinksShouldWorkOnAFrozenGraph(t *testing.T) {
+	const n = 500
+	edges := incrementalBatch(n)
+
+	pageRank := New()
+	buildTestGraph(pageRank, n)
+	pageRank.Freeze()
+	pageRank.Rank(0.85, 1e-10, func(_ int, _ float64) {})
+
+	pageRank.ApplyLinks(edges)
+
+	assertCloseToFullRecompute(t, pageRank.CurrentRanks(), n, edges, 1e-6)
+}
@@ -0,0 +1,59 @@
+package pagerank
+
+// DanglingStrategy decide qué pasa con el rank de los nodos colgantes (sin
+// enlaces salientes) en cada paso
+type DanglingStrategy int
+
+const (
+	// DanglingUniform reparte la masa colgante entre todos los nodos
+	DanglingUniform DanglingStrategy = iota
+	// DanglingPersonalized la reparte según el vector de personalización
+	// (uniforme si no hay personalización)
+	DanglingPersonalized
+	// DanglingSelfLoop trata cada nodo colgante como si se enlazara a sí mismo
+	DanglingSelfLoop
+	// DanglingDrop descarta la masa colgante y renormaliza el vector en cada
+	// paso, lo que equivale a repartirla en proporción al rank actual
+	DanglingDrop
+)
+
+func (s DanglingStrategy) String() string {
+	switch s {
+	case DanglingPersonalized:
+		return "personalized"
+	case DanglingSelfLoop:
+		return "self-loop"
+	case DanglingDrop:
+		return "drop"
+	default:
+		return "uniform"
+	}
+}
+
+// danglingStrategy resuelve la estrategia efectiva: PersonalizedDangling es la
+// forma antigua de pedir DanglingPersonalized
+func (opts RankOptionsOf[K]) danglingStrategy() DanglingStrategy {
+	if opts.Dangling == DanglingUniform && opts.PersonalizedDangling {
+		return DanglingPersonalized
+	}
+	return opts.Dangling
+}
+
+// danglingTerm devuelve la masa colgante que recibe el nodo i, siendo
+// innerProduct la masa total de los nodos colgantes en p
+func (t teleport) danglingTerm(i int, innerProduct float64, c *csr, p []float64) float64 {
+	switch t.dangling {
+	case DanglingPersonalized:
+		return innerProduct * t.vector[i]
+	case DanglingSelfLoop:
+		// Sólo los nodos colgantes tienen inverseOutLinks en 0
+		if c.inverseOutLinks[i] == 0 {
+			return p[i]
+		}
+		return 0
+	case DanglingDrop:
+		return 0
+	default:
+		return innerProduct * t.uniform
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/dcadenas/pagerank"
 )
 
 // Analizador procesa los resultados y calcula métricas agregadas
@@ -211,6 +213,38 @@ func (a *Analizador) ImprimirResultados(metricas []MetricasAgregadas) {
 	}
 }
 
+// ImprimirComparacionSolvers muestra iteraciones y tiempos de cada solver
+// agrupados por tamaño de grafo e implementación
+func (a *Analizador) ImprimirComparacionSolvers(resultados []ResultadoEjecucion) {
+	fmt.Println("\n=== COMPARACIÓN DE SOLVERS ===")
+	fmt.Printf("%-10s %-14s %-12s %-12s %-14s %-14s\n",
+		"Tamaño", "Implementación", "Solver", "Iteraciones", "Tiempo", "Tiempo/Iter")
+	fmt.Println(strings.Repeat("-", 80))
+
+	for _, r := range resultados {
+		fmt.Printf("%-10s %-14s %-12s %-12d %-14v %-14v\n",
+			r.TamanoGrafo, r.Implementacion, r.Solver, r.Iteraciones,
+			r.TiempoEjecucion.Round(time.Microsecond), r.TiempoIteracion.Round(time.Microsecond))
+	}
+}
+
+// ImprimirComparacionOrdenes muestra el tiempo por iteración de cada
+// renumeración y lo que costó calcularla, agrupados por tamaño de grafo e
+// implementación
+func (a *Analizador) ImprimirComparacionOrdenes(resultados []ResultadoEjecucion) {
+	fmt.Println("\n=== COMPARACIÓN DE ÓRDENES DE NODOS ===")
+	fmt.Printf("%-10s %-14s %-8s %-12s %-14s %-14s %-14s\n",
+		"Tamaño", "Implementación", "Orden", "Iteraciones", "Tiempo", "Tiempo/Iter", "Reorden")
+	fmt.Println(strings.Repeat("-", 92))
+
+	for _, r := range resultados {
+		fmt.Printf("%-10s %-14s %-8s %-12d %-14v %-14v %-14v\n",
+			r.TamanoGrafo, r.Implementacion, r.Orden, r.Iteraciones,
+			r.TiempoEjecucion.Round(time.Microsecond), r.TiempoIteracion.Round(time.Microsecond),
+			r.TiempoReorden.Round(time.Microsecond))
+	}
+}
+
 // VerificarCorreccion verifica que las versiones produzcan el mismo orden de nodos
 func (a *Analizador) VerificarCorreccion() bool {
 	// Agrupar por tamaño de grafo
@@ -249,24 +283,10 @@ func (a *Analizador) VerificarCorreccion() bool {
 }
 
 // obtenerTopNodos obtiene los N nodos con mayor rank
-func obtenerTopNodos(ranks map[int]float64, n int) []int {
-	type nodoRank struct {
-		nodo int
-		rank float64
-	}
-	
-	lista := make([]nodoRank, 0, len(ranks))
-	for nodo, rank := range ranks {
-		lista = append(lista, nodoRank{nodo, rank})
-	}
-	
-	sort.Slice(lista, func(i, j int) bool {
-		return lista[i].rank > lista[j].rank
-	})
-	
+func obtenerTopNodos(ranks *pagerank.Ranks[int], n int) []int {
 	resultado := make([]int, 0, n)
-	for i := 0; i < n && i < len(lista); i++ {
-		resultado = append(resultado, lista[i].nodo)
+	for _, nodo := range ranks.TopK(n) {
+		resultado = append(resultado, nodo.Key)
 	}
 	
 	return resultado
@@ -308,6 +328,18 @@ func (a *Analizador) GuardarCSV(nombreArchivo string, resultados []ResultadoEjec
 		"num_nodos",
 		"num_enlaces",
 		"memoria_mb",
+		"iteraciones",
+		"residual_final",
+		"tiempo_iteracion_ms",
+		"solver",
+		"reparto",
+		"desbalance",
+		"kernel",
+		"orden",
+		"tiempo_reorden_ms",
+		"adaptativo",
+		"omitidas",
+		"cota_adaptativa",
 	}
 	if err := writer.Write(headers); err != nil {
 		return fmt.Errorf("error escribiendo encabezados: %v", err)
@@ -324,6 +356,18 @@ func (a *Analizador) GuardarCSV(nombreArchivo string, resultados []ResultadoEjec
 			strconv.Itoa(r.NumNodos),
 			strconv.Itoa(r.NumEnlaces),
 			fmt.Sprintf("%.2f", float64(r.MemoriaUsada)/(1024*1024)),
+			strconv.Itoa(r.Iteraciones),
+			fmt.Sprintf("%.3e", r.ResidualFinal),
+			fmt.Sprintf("%.3f", float64(r.TiempoIteracion.Microseconds())/1000.0),
+			r.Solver.String(),
+			r.Reparto.String(),
+			fmt.Sprintf("%.3f", r.Desbalance),
+			r.Kernel.String(),
+			r.Orden.String(),
+			fmt.Sprintf("%.2f", float64(r.TiempoReorden.Microseconds())/1000.0),
+			strconv.FormatBool(r.Adaptativo),
+			strconv.Itoa(r.Omitidas),
+			fmt.Sprintf("%.3e", r.CotaAdaptativa),
 		}
 		if err := writer.Write(record); err != nil {
 			return fmt.Errorf("error escribiendo registro: %v", err)
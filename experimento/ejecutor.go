@@ -1,6 +1,7 @@
 package experimento
 
 import (
+	"context"
 	"fmt"
 	"runtime"
 	"time"
@@ -67,6 +68,8 @@ func (e *Ejecutor) EjecutarExperimentoCompleto() []ResultadoEjecucion {
 		trat := Tratamiento{
 			Implementacion: impl,
 			NumGoroutines:  corrida.workers,
+			Solver:         e.config.Solver,
+			Orden:          e.config.Orden,
 		}
 		
 		resultado := e.ejecutarTratamiento(grafo, trat, corrida.replica)
@@ -83,6 +86,8 @@ func (e *Ejecutor) EjecutarExperimentoCompleto() []ResultadoEjecucion {
 type Tratamiento struct {
 	Implementacion TipoImplementacion
 	NumGoroutines  int
+	Solver         pagerank.Solver
+	Orden          pagerank.Ordering
 }
 
 // crearTratamientos genera todas las combinaciones de tratamientos
@@ -115,17 +120,17 @@ func (e *Ejecutor) ejecutarTratamiento(grafo *Grafo, trat Tratamiento, replica i
 		pr = pagerank.New()
 	}
 	
-	// Cargar el grafo
-	for _, enlace := range grafo.Enlaces {
-		pr.Link(enlace[0], enlace[1])
-	}
+	// Cargar el grafo, renumerarlo y congelarlo a su forma compacta (CSR)
+	pr.AddEdges(grafo.Enlaces)
+	inicioReorden := time.Now()
+	pr.Reorder(trat.Orden)
+	tiempoReorden := time.Since(inicioReorden)
+	pr.Freeze()
 	
 	// WARM-UP: Ejecutar una vez sin medir (solo en la primera réplica)
 	// Esto calienta la CPU, carga caches, etc.
 	if replica == 1 {
-		pr.Rank(e.config.DampingFactor, e.config.Tolerance, func(nodeID int, rank float64) {
-			// Descartamos estos resultados
-		})
+		e.rankear(pr, trat) // Descartamos estos resultados
 		
 		// Recrear para la medición real
 		if trat.Implementacion == Concurrente {
@@ -133,16 +138,17 @@ func (e *Ejecutor) ejecutarTratamiento(grafo *Grafo, trat Tratamiento, replica i
 		} else {
 			pr = pagerank.New()
 		}
-		for _, enlace := range grafo.Enlaces {
-			pr.Link(enlace[0], enlace[1])
-		}
+		pr.AddEdges(grafo.Enlaces)
+		pr.Reorder(trat.Orden)
+		pr.Freeze()
 	}
 	
 	// Almacenar resultados
-	resultadosRank := make(map[int]float64)
+	var resultadosRank *pagerank.Ranks[int]
 	
 	var duracion time.Duration
 	var memoria uint64
+	var estadisticas pagerank.RankStats
 	
 	// DECISIÓN: Para grafos pequeños, usar repeticiones para mejor precisión
 	esGrafoPequeno := grafo.NumNodos <= 50000
@@ -156,10 +162,7 @@ func (e *Ejecutor) ejecutarTratamiento(grafo *Grafo, trat Tratamiento, replica i
 		
 		duracion = MedirConRepeticiones(func() {
 			// Recrear resultados en cada iteración
-			resultadosRank = make(map[int]float64)
-			pr.Rank(e.config.DampingFactor, e.config.Tolerance, func(nodeID int, rank float64) {
-				resultadosRank[nodeID] = rank
-			})
+			resultadosRank, estadisticas = e.rankear(pr, trat)
 		}, repeticiones)
 		
 		// Memoria: medir una vez (no cambia significativamente)
@@ -167,9 +170,7 @@ func (e *Ejecutor) ejecutarTratamiento(grafo *Grafo, trat Tratamiento, replica i
 		runtime.ReadMemStats(&m)
 		memoriaInicio := m.Alloc
 		
-		pr.Rank(e.config.DampingFactor, e.config.Tolerance, func(nodeID int, rank float64) {
-			resultadosRank[nodeID] = rank
-		})
+		resultadosRank, _ = e.rankear(pr, trat)
 		
 		runtime.ReadMemStats(&m)
 		memoria = m.Alloc - memoriaInicio
@@ -178,9 +179,7 @@ func (e *Ejecutor) ejecutarTratamiento(grafo *Grafo, trat Tratamiento, replica i
 		// Grafos medianos/grandes: medición normal (suficiente precisión)
 		medidor := NuevoMedidor()
 		
-		pr.Rank(e.config.DampingFactor, e.config.Tolerance, func(nodeID int, rank float64) {
-			resultadosRank[nodeID] = rank
-		})
+		resultadosRank, estadisticas = e.rankear(pr, trat)
 		
 		duracion, memoria = medidor.Detener()
 	}
@@ -189,13 +188,138 @@ func (e *Ejecutor) ejecutarTratamiento(grafo *Grafo, trat Tratamiento, replica i
 		TamanoGrafo:     obtenerTamanoPorNumNodos(grafo.NumNodos),
 		Implementacion:  trat.Implementacion,
 		NumGoroutines:   trat.NumGoroutines,
+		Solver:          trat.Solver,
 		Replica:         replica,
 		TiempoEjecucion: duracion,
 		NumNodos:        grafo.NumNodos,
 		NumEnlaces:      grafo.NumEnlaces,
 		MemoriaUsada:    memoria,
 		ResultadosRank:  resultadosRank,
+		Iteraciones:     estadisticas.Iterations,
+		ResidualFinal:   estadisticas.Residual,
+		TiempoIteracion: tiempoPromedioIteracion(estadisticas),
+		Reparto:         e.config.Reparto,
+		Desbalance:      desbalance(estadisticas),
+		Kernel:          e.config.Kernel,
+		Orden:           trat.Orden,
+		TiempoReorden:   tiempoReorden,
+		Adaptativo:      e.config.Adaptativo,
+		Omitidas:        estadisticas.SkippedUpdates,
+		CotaAdaptativa:  estadisticas.AdaptiveBound,
+	}
+}
+
+// rankear ejecuta PageRank con los parámetros del experimento y el solver del
+// tratamiento
+func (e *Ejecutor) rankear(pr pagerank.Interface, trat Tratamiento) (*pagerank.Ranks[int], pagerank.RankStats) {
+	ranks, estadisticas, _ := pr.RankResults(context.Background(), pagerank.RankOptions{
+		FollowingProb: e.config.DampingFactor,
+		Tolerance:     e.config.Tolerance,
+		Solver:        trat.Solver,
+		Omega:         e.config.Omega,
+		Scheduling:    e.config.Reparto,
+		Kernel:        e.config.Kernel,
+		Adaptive:      e.config.Adaptativo,
+	})
+	return ranks, estadisticas
+}
+
+// CompararSolvers ejecuta cada solver sobre los tres tamaños de grafo, en la
+// versión secuencial y en la concurrente, para comparar iteraciones y tiempo
+func (e *Ejecutor) CompararSolvers() []ResultadoEjecucion {
+	workers := e.config.NumGoroutines
+	if workers < 2 {
+		workers = runtime.NumCPU()
+	}
+
+	solvers := []pagerank.Solver{pagerank.PowerIteration, pagerank.GaussSeidel, pagerank.SOR}
+	resultados := make([]ResultadoEjecucion, 0)
+
+	for _, tamano := range []TamanoGrafo{Pequeno, Mediano, Grande} {
+		grafo := e.generador.GenerarGrafo(ObtenerConfiguracionPorTamano(tamano, time.Now().UnixNano()))
+		fmt.Printf("Generado: Grafo %s - %d nodos, %d enlaces\n", tamano, grafo.NumNodos, grafo.NumEnlaces)
+
+		for _, solver := range solvers {
+			for _, trat := range []Tratamiento{
+				{Implementacion: Secuencial, NumGoroutines: 1, Solver: solver, Orden: e.config.Orden},
+				{Implementacion: Concurrente, NumGoroutines: workers, Solver: solver, Orden: e.config.Orden},
+			} {
+				resultado := e.ejecutarTratamiento(grafo, trat, 1)
+				resultados = append(resultados, resultado)
+
+				fmt.Printf("%8s | %-12s | %2d workers | %4d iteraciones | %v\n",
+					tamano, solver, trat.NumGoroutines, resultado.Iteraciones, resultado.TiempoEjecucion)
+			}
+		}
 	}
+
+	return resultados
+}
+
+// CompararOrdenes ejecuta cada renumeración de nodos sobre los tres tamaños
+// de grafo, en la versión secuencial y en la concurrente, para medir cuánto
+// cambia el tiempo por iteración y cuánto cuesta renumerar
+func (e *Ejecutor) CompararOrdenes() []ResultadoEjecucion {
+	workers := e.config.NumGoroutines
+	if workers < 2 {
+		workers = runtime.NumCPU()
+	}
+
+	ordenes := []pagerank.Ordering{pagerank.NoOrdering, pagerank.DegreeOrdering, pagerank.RCMOrdering, pagerank.GorderOrdering}
+	resultados := make([]ResultadoEjecucion, 0)
+
+	for _, tamano := range []TamanoGrafo{Pequeno, Mediano, Grande} {
+		grafo := e.generador.GenerarGrafo(ObtenerConfiguracionPorTamano(tamano, time.Now().UnixNano()))
+		fmt.Printf("Generado: Grafo %s - %d nodos, %d enlaces\n", tamano, grafo.NumNodos, grafo.NumEnlaces)
+
+		for _, orden := range ordenes {
+			for _, trat := range []Tratamiento{
+				{Implementacion: Secuencial, NumGoroutines: 1, Solver: e.config.Solver, Orden: orden},
+				{Implementacion: Concurrente, NumGoroutines: workers, Solver: e.config.Solver, Orden: orden},
+			} {
+				resultado := e.ejecutarTratamiento(grafo, trat, 1)
+				resultados = append(resultados, resultado)
+
+				fmt.Printf("%8s | %-6s | %2d workers | reorden %v | %v\n",
+					tamano, orden, trat.NumGoroutines, resultado.TiempoReorden, resultado.TiempoEjecucion)
+			}
+		}
+	}
+
+	return resultados
+}
+
+// tiempoPromedioIteracion calcula el tiempo medio de cada iteración de Rank
+func tiempoPromedioIteracion(estadisticas pagerank.RankStats) time.Duration {
+	if estadisticas.Iterations == 0 {
+		return 0
+	}
+
+	var total time.Duration
+	for _, t := range estadisticas.IterationTimes {
+		total += t
+	}
+
+	return total / time.Duration(estadisticas.Iterations)
+}
+
+// desbalance compara el tiempo de trabajo del worker más cargado con el
+// promedio de todos los workers
+func desbalance(estadisticas pagerank.RankStats) float64 {
+	if len(estadisticas.WorkerLoads) == 0 {
+		return 0
+	}
+
+	var total, maximo time.Duration
+	for _, carga := range estadisticas.WorkerLoads {
+		total += carga.Busy
+		maximo = max(maximo, carga.Busy)
+	}
+	if total == 0 {
+		return 0
+	}
+
+	return float64(maximo) * float64(len(estadisticas.WorkerLoads)) / float64(total)
 }
 
 // obtenerTamanoPorNumNodos determina el tamaño del grafo por su número de nodos
@@ -212,8 +336,6 @@ func obtenerTamanoPorNumNodos(numNodos int) TamanoGrafo {
 // CrearPageRankDesdeGrafo crea una instancia de PageRank y carga el grafo
 func CrearPageRankDesdeGrafo(grafo *Grafo) pagerank.Interface {
 	pr := pagerank.New()
-	for _, enlace := range grafo.Enlaces {
-		pr.Link(enlace[0], enlace[1])
-	}
+	pr.AddEdges(grafo.Enlaces)
 	return pr
 }
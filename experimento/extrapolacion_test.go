@@ -0,0 +1,46 @@
+package experimento
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dcadenas/pagerank"
+)
+
+// grafosBenchmark guarda los grafos sintéticos ya generados por tamaño
+var grafosBenchmark = make(map[TamanoGrafo]*Grafo)
+
+func grafoBenchmark(tamano TamanoGrafo) *Grafo {
+	if grafo, ok := grafosBenchmark[tamano]; ok {
+		return grafo
+	}
+	grafo := NewGenerador(42).GenerarGrafo(ObtenerConfiguracionPorTamano(tamano, 42))
+	grafosBenchmark[tamano] = grafo
+	return grafo
+}
+
+// BenchmarkExtrapolacion compara las iteraciones de power iteration con y sin
+// extrapolación sobre los grafos del experimento. Reporta iteraciones/op:
+//
+//	go test -run xxx -bench Extrapolacion ./experimento/
+func BenchmarkExtrapolacion(b *testing.B) {
+	for _, tamano := range []TamanoGrafo{Pequeno, Mediano} {
+		for _, extrapolacion := range []pagerank.Extrapolation{pagerank.NoExtrapolation, pagerank.Aitken, pagerank.Quadratic} {
+			b.Run(string(tamano)+"/"+extrapolacion.String(), func(b *testing.B) {
+				pr := CrearPageRankDesdeGrafo(grafoBenchmark(tamano))
+				pr.Freeze()
+
+				var estadisticas pagerank.RankStats
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					_, estadisticas, _ = pr.RankContext(context.Background(), pagerank.RankOptions{
+						FollowingProb: 0.85,
+						Tolerance:     1e-8,
+						Extrapolation: extrapolacion,
+					})
+				}
+				b.ReportMetric(float64(estadisticas.Iterations), "iteraciones/op")
+			})
+		}
+	}
+}
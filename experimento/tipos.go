@@ -1,6 +1,11 @@
 package experimento
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"github.com/dcadenas/pagerank"
+)
 
 // TamanoGrafo representa los diferentes tamaños de grafos para los bloques
 type TamanoGrafo string
@@ -37,10 +42,58 @@ type ConfigGrafo struct {
 
 // ConfigExperimento define los parámetros del experimento
 type ConfigExperimento struct {
-	DampingFactor float64 // Factor de amortiguación (típicamente 0.85)
-	Tolerance     float64 // Tolerancia para convergencia
-	NumGoroutines int     // Número de goroutines (solo para versión concurrente)
-	NumReplicas   int     // Número de réplicas por combinación
+	DampingFactor float64             // Factor de amortiguación (típicamente 0.85)
+	Tolerance     float64             // Tolerancia para convergencia
+	NumGoroutines int                 // Número de goroutines (solo para versión concurrente)
+	NumReplicas   int                 // Número de réplicas por combinación
+	Solver        pagerank.Solver     // Método de iteración del experimento principal
+	Omega         float64             // Factor de relajación para SOR
+	Reparto       pagerank.Scheduling // Reparto del trabajo entre workers
+	Kernel        pagerank.Kernel     // Kernel de la iteración de potencias (pull o push)
+	Orden         pagerank.Ordering   // Renumeración de los nodos antes de iterar
+	Adaptativo    bool                // Congelar los nodos que ya convergieron
+}
+
+// ParsearSolver traduce el nombre de un solver ("power", "gauss-seidel", "sor")
+func ParsearSolver(nombre string) (pagerank.Solver, error) {
+	for _, solver := range []pagerank.Solver{pagerank.PowerIteration, pagerank.GaussSeidel, pagerank.SOR} {
+		if solver.String() == nombre {
+			return solver, nil
+		}
+	}
+	return pagerank.PowerIteration, fmt.Errorf("solver desconocido: %s", nombre)
+}
+
+// ParsearReparto traduce el nombre de un reparto ("edge-balanced",
+// "node-balanced", "dynamic")
+func ParsearReparto(nombre string) (pagerank.Scheduling, error) {
+	for _, reparto := range []pagerank.Scheduling{pagerank.EdgeBalanced, pagerank.NodeBalanced, pagerank.DynamicScheduling} {
+		if reparto.String() == nombre {
+			return reparto, nil
+		}
+	}
+	return pagerank.EdgeBalanced, fmt.Errorf("reparto desconocido: %s", nombre)
+}
+
+// ParsearKernel traduce el nombre de un kernel ("pull", "push")
+func ParsearKernel(nombre string) (pagerank.Kernel, error) {
+	for _, kernel := range []pagerank.Kernel{pagerank.PullKernel, pagerank.PushKernel} {
+		if kernel.String() == nombre {
+			return kernel, nil
+		}
+	}
+	return pagerank.PullKernel, fmt.Errorf("kernel desconocido: %s", nombre)
+}
+
+// ParsearOrden traduce el nombre de una renumeración ("none", "degree",
+// "rcm", "gorder")
+func ParsearOrden(nombre string) (pagerank.Ordering, error) {
+	for _, orden := range []pagerank.Ordering{pagerank.NoOrdering, pagerank.DegreeOrdering, pagerank.RCMOrdering, pagerank.GorderOrdering} {
+		if orden.String() == nombre {
+			return orden, nil
+		}
+	}
+	return pagerank.NoOrdering, fmt.Errorf("orden desconocido: %s", nombre)
 }
 
 // ResultadoEjecucion almacena las métricas de una ejecución
@@ -48,12 +101,24 @@ type ResultadoEjecucion struct {
 	TamanoGrafo     TamanoGrafo
 	Implementacion  TipoImplementacion
 	NumGoroutines   int
+	Solver          pagerank.Solver
 	Replica         int
 	TiempoEjecucion time.Duration
 	NumNodos        int
 	NumEnlaces      int
 	MemoriaUsada    uint64 // Bytes
-	ResultadosRank  map[int]float64 // NodeID -> Rank
+	ResultadosRank  *pagerank.Ranks[int]
+	Iteraciones     int           // Iteraciones hasta converger
+	ResidualFinal   float64       // Cambio L1 de la última iteración
+	TiempoIteracion time.Duration // Tiempo promedio por iteración
+	Reparto         pagerank.Scheduling
+	Desbalance      float64 // Tiempo del worker más cargado sobre el promedio (1 = parejo, 0 sin workers)
+	Kernel          pagerank.Kernel
+	Orden           pagerank.Ordering
+	TiempoReorden   time.Duration // Tiempo de Reorder, fuera de TiempoEjecucion
+	Adaptativo      bool
+	Omitidas        int     // Actualizaciones de nodos congelados que no se calcularon
+	CotaAdaptativa  float64 // Cota de la distancia L1 al PageRank sin congelar
 }
 
 // MetricasAgregadas contiene las métricas calculadas después del experimento
@@ -0,0 +1,194 @@
+package pagerank
+
+import "math"
+
+// Extrapolation selecciona la aceleración periódica de la iteración
+// (Kamvar, Haveliwala, Manning y Golub, "Extrapolation Methods for
+// Accelerating PageRank Computations", 2003)
+type Extrapolation int
+
+const (
+	// NoExtrapolation itera sin acelerar
+	NoExtrapolation Extrapolation = iota
+	// Aitken estima el límite de cada componente a partir de los tres
+	// últimos iterados, suponiendo que el error lo domina un solo autovector
+	Aitken
+	// Quadratic estima el límite a partir de los cuatro últimos iterados
+	// eliminando las componentes de los autovectores segundo y tercero
+	Quadratic
+)
+
+// defaultExtrapolationPeriod es el número de iteraciones entre
+// extrapolaciones cuando ExtrapolationPeriod es 0
+const defaultExtrapolationPeriod = 10
+
+func (x Extrapolation) String() string {
+	switch x {
+	case Aitken:
+		return "aitken"
+	case Quadratic:
+		return "quadratic"
+	default:
+		return "none"
+	}
+}
+
+// iterates es el número de iterados consecutivos que necesita el método
+func (x Extrapolation) iterates() int {
+	switch x {
+	case Aitken:
+		return 3
+	case Quadratic:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// extrapolator guarda copias de los últimos iterados y cada period
+// iteraciones sustituye el vector por su extrapolación. Sólo copia los
+// iterados que va a usar, en búferes que reutiliza.
+type extrapolator struct {
+	method  Extrapolation
+	period  int
+	history [][]float64 // Iterados consecutivos, el más reciente al final
+	free    [][]float64 // Búferes disponibles para history
+}
+
+// newExtrapolator devuelve nil si no se pidió extrapolación
+func newExtrapolator(method Extrapolation, period int) *extrapolator {
+	if method.iterates() == 0 {
+		return nil
+	}
+	if period <= 0 {
+		period = defaultExtrapolationPeriod
+	}
+	if period < method.iterates() {
+		period = method.iterates()
+	}
+
+	return &extrapolator{
+		method:  method,
+		period:  period,
+		history: make([][]float64, 0, method.iterates()),
+	}
+}
+
+// observe registra el iterado p de la iteración indicada (contando desde 1) y,
+// cuando toca, escribe en p su extrapolación y devuelve true
+func (x *extrapolator) observe(iteration int, p []float64) bool {
+	if x == nil {
+		return false
+	}
+
+	need := x.method.iterates()
+	step := iteration % x.period
+	if step != 0 && step <= x.period-need {
+		return false
+	}
+
+	var buffer []float64
+	if len(x.history) == need {
+		buffer = x.history[0]
+		copy(x.history, x.history[1:])
+		x.history = x.history[:need-1]
+	} else if len(x.free) > 0 {
+		buffer = x.free[len(x.free)-1]
+		x.free = x.free[:len(x.free)-1]
+	}
+	x.history = append(x.history, append(buffer[:0], p...))
+
+	if step != 0 || len(x.history) < need {
+		return false
+	}
+
+	extrapolated := false
+	switch x.method {
+	case Aitken:
+		extrapolated = aitken(x.history[0], x.history[1], x.history[2], p)
+	case Quadratic:
+		extrapolated = quadratic(x.history[0], x.history[1], x.history[2], x.history[3], p)
+	}
+
+	// El vector extrapolado no es un iterado: la siguiente extrapolación
+	// tiene que partir de iterados nuevos
+	x.free = append(x.free, x.history...)
+	x.history = x.history[:0]
+	return extrapolated
+}
+
+// aitken aplica la extrapolación de Aitken componente a componente:
+// x* = x0 - (x1-x0)^2 / (x2 - 2*x1 + x0), escrita en extrapolated
+func aitken(x0, x1, x2, extrapolated []float64) bool {
+	for i := range extrapolated {
+		extrapolated[i] = x2[i]
+
+		g := x1[i] - x0[i]
+		h := x2[i] - 2*x1[i] + x0[i]
+
+		// Sólo si la componente se contrae geométricamente: con razón
+		// (x2-x1)/(x1-x0) fuera de (-1, 1) la estimación no tiene sentido
+		if ratio := (x2[i] - x1[i]) / g; g == 0 || math.Abs(ratio) >= 1 {
+			continue
+		}
+		if value := x0[i] - g*g/h; value > 0 {
+			extrapolated[i] = value
+		}
+	}
+
+	normalize(extrapolated)
+	return true
+}
+
+// quadratic aplica la extrapolación cuadrática: resuelve por mínimos
+// cuadrados los coeficientes del polinomio característico de grado 2 y
+// combina los tres últimos iterados con ellos, escribiendo en extrapolated.
+// Devuelve false sin tocarlo si el sistema es singular.
+func quadratic(x0, x1, x2, x3, extrapolated []float64) bool {
+	var a11, a12, a22, b1, b2 float64
+	for i := range x3 {
+		y1 := x1[i] - x0[i]
+		y2 := x2[i] - x0[i]
+		y3 := x3[i] - x0[i]
+
+		a11 += y1 * y1
+		a12 += y1 * y2
+		a22 += y2 * y2
+		b1 += y1 * y3
+		b2 += y2 * y3
+	}
+
+	// Ecuaciones normales de min ||gamma1*y1 + gamma2*y2 + y3||
+	det := a11*a22 - a12*a12
+	if det == 0 || math.IsNaN(det) {
+		return false
+	}
+	gamma1 := (-b1*a22 + b2*a12) / det
+	gamma2 := (-b2*a11 + b1*a12) / det
+
+	beta0 := gamma1 + gamma2 + 1
+	beta1 := gamma2 + 1
+
+	for i := range extrapolated {
+		extrapolated[i] = x3[i]
+		if value := beta0*x1[i] + beta1*x2[i] + x3[i]; value > 0 {
+			extrapolated[i] = value
+		}
+	}
+
+	normalize(extrapolated)
+	return true
+}
+
+// normalize escala p para que sume 1
+func normalize(p []float64) {
+	sum := 0.0
+	for _, pForI := range p {
+		sum += pForI
+	}
+
+	inverseOfSum := 1.0 / sum
+	for i := range p {
+		p[i] *= inverseOfSum
+	}
+}
@@ -0,0 +1,351 @@
+package pagerank
+
+import (
+	"math"
+	"sync"
+)
+
+// graph almacena la estructura de enlaces que comparten la versión secuencial
+// y la concurrente. Los nodos se guardan por índice de arreglo y las claves
+// originales se traducen con keyToIndex / indexToKey. Las listas de adyacencia
+// son el constructor mutable; los motores iteran sobre su forma CSR.
+type graph[K comparable] struct {
+	inLinks               [][]int
+	inLinkWeights         [][]float64 // nil mientras todos los enlaces pesen 1
+	numberOutLinks        []int
+	outLinkWeights        []float64 // suma de pesos salientes, nil sin pesos
+	currentAvailableIndex int
+	keyToIndex            map[K]int
+	indexToKey            []K
+	csr                   *csr        // forma congelada, nil si el grafo cambió
+	frozen                bool        // las listas de adyacencia se liberaron
+	outLinks              [][]outLink // sólo se construye para ApplyLinks
+	lastRanks             []float64   // último vector calculado, por índice
+	lastRanksStale        bool        // el grafo cambió desde lastRanks
+	lastOptions           RankOptionsOf[K]
+	scratch               []float64  // segundo búfer de la iteración, se reutiliza
+	ranking               sync.Mutex // serializa los cálculos, que comparten scratch y el motor
+	options               GraphOptions
+	duplicatesPending     bool  // hay enlaces repetidos sin compactar
+	err                   error // primer enlace rechazado, ver Err
+}
+
+func (g *graph[K]) keyAsArrayIndex(key K) int {
+	index, ok := g.keyToIndex[key]
+
+	if !ok {
+		index = g.currentAvailableIndex
+		g.currentAvailableIndex++
+		g.keyToIndex[key] = index
+		g.indexToKey = append(g.indexToKey, key)
+	}
+
+	return index
+}
+
+func (g *graph[K]) updateInLinks(fromAsIndex, toAsIndex int, weight float64) {
+	missingSlots := len(g.keyToIndex) - len(g.inLinks)
+
+	if missingSlots > 0 {
+		g.inLinks = append(g.inLinks, make([][]int, missingSlots)...)
+	}
+
+	g.inLinks[toAsIndex] = append(g.inLinks[toAsIndex], fromAsIndex)
+
+	if g.inLinkWeights != nil {
+		missingSlots := len(g.inLinks) - len(g.inLinkWeights)
+
+		if missingSlots > 0 {
+			g.inLinkWeights = append(g.inLinkWeights, make([][]float64, missingSlots)...)
+		}
+
+		g.inLinkWeights[toAsIndex] = append(g.inLinkWeights[toAsIndex], weight)
+	}
+}
+
+func (g *graph[K]) updateNumberOutLinks(fromAsIndex int, weight float64) {
+	missingSlots := len(g.keyToIndex) - len(g.numberOutLinks)
+
+	if missingSlots > 0 {
+		g.numberOutLinks = append(g.numberOutLinks, make([]int, missingSlots)...)
+	}
+
+	g.numberOutLinks[fromAsIndex] += 1
+
+	if g.outLinkWeights != nil {
+		missingSlots := len(g.numberOutLinks) - len(g.outLinkWeights)
+
+		if missingSlots > 0 {
+			g.outLinkWeights = append(g.outLinkWeights, make([]float64, missingSlots)...)
+		}
+
+		g.outLinkWeights[fromAsIndex] += weight
+	}
+}
+
+// enableWeights pasa el grafo a modo ponderado: todos los enlaces existentes
+// conservan peso 1.
+func (g *graph[K]) enableWeights() {
+	g.inLinkWeights = make([][]float64, len(g.inLinks))
+	for i, inLinksForI := range g.inLinks {
+		weights := make([]float64, len(inLinksForI))
+		for j := range weights {
+			weights[j] = 1.0
+		}
+		g.inLinkWeights[i] = weights
+	}
+
+	g.outLinkWeights = make([]float64, len(g.numberOutLinks))
+	for i, numberOutLinksForI := range g.numberOutLinks {
+		g.outLinkWeights[i] = float64(numberOutLinksForI)
+	}
+}
+
+func (g *graph[K]) linkWithIndices(fromAsIndex, toAsIndex int, weight float64) {
+	g.beginMutation()
+	g.updateInLinks(fromAsIndex, toAsIndex, weight)
+	g.updateNumberOutLinks(fromAsIndex, weight)
+
+	if g.outLinks != nil {
+		g.updateOutLinks(fromAsIndex, toAsIndex, weight)
+	}
+	if g.options.Duplicates != KeepDuplicates {
+		g.duplicatesPending = true
+	}
+}
+
+func (g *graph[K]) Link(from, to K) {
+	if !g.acceptLink(from, to) {
+		return
+	}
+
+	fromAsIndex := g.keyAsArrayIndex(from)
+	toAsIndex := g.keyAsArrayIndex(to)
+
+	g.linkWithIndices(fromAsIndex, toAsIndex, 1.0)
+}
+
+// LinkWeighted agrega un enlace con peso: la probabilidad de seguir cada
+// enlace saliente de from es proporcional a su peso. Los pesos no positivos
+// o no finitos se ignoran.
+func (g *graph[K]) LinkWeighted(from, to K, weight float64) {
+	if !(weight > 0) || math.IsInf(weight, 0) || !g.acceptLink(from, to) {
+		return
+	}
+
+	g.beginMutation()
+	if g.inLinkWeights == nil && weight != 1.0 {
+		g.enableWeights()
+	}
+
+	fromAsIndex := g.keyAsArrayIndex(from)
+	toAsIndex := g.keyAsArrayIndex(to)
+
+	g.linkWithIndices(fromAsIndex, toAsIndex, weight)
+}
+
+// Unlink elimina un enlace de from a to (una sola ocurrencia, simétrico a
+// Link). Los nodos se conservan aunque queden sin enlaces; para quitarlos se
+// usa RemoveNode.
+func (g *graph[K]) Unlink(from, to K) {
+	fromAsIndex, ok := g.keyToIndex[from]
+	if !ok {
+		return
+	}
+	toAsIndex, ok := g.keyToIndex[to]
+	if !ok {
+		return
+	}
+
+	g.beginMutation()
+	g.compactDuplicates()
+
+	position := -1
+	for j, index := range g.inLinks[toAsIndex] {
+		if index == fromAsIndex {
+			position = j
+			break
+		}
+	}
+	if position < 0 {
+		return
+	}
+
+	weight := 1.0
+	g.inLinks[toAsIndex] = append(g.inLinks[toAsIndex][:position], g.inLinks[toAsIndex][position+1:]...)
+	if g.inLinkWeights != nil {
+		weights := g.inLinkWeights[toAsIndex]
+		weight = weights[position]
+		g.inLinkWeights[toAsIndex] = append(weights[:position], weights[position+1:]...)
+	}
+
+	g.numberOutLinks[fromAsIndex] -= 1
+	if g.outLinkWeights != nil {
+		g.outLinkWeights[fromAsIndex] -= weight
+		if g.numberOutLinks[fromAsIndex] == 0 {
+			// Evitar que quede un residuo de redondeo en un nodo colgante
+			g.outLinkWeights[fromAsIndex] = 0
+		}
+	}
+
+	if g.outLinks != nil {
+		outLinks := g.outLinks[fromAsIndex]
+		for k, link := range outLinks {
+			if link.to == toAsIndex {
+				g.outLinks[fromAsIndex] = append(outLinks[:k], outLinks[k+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// RemoveNode elimina el nodo y todos sus enlaces. Para no dejar huecos, el
+// nodo con el último índice pasa a ocupar el índice liberado, así que los
+// arreglos siempre quedan compactos. Cuesta O(nodos + enlaces).
+func (g *graph[K]) RemoveNode(key K) {
+	index, ok := g.keyToIndex[key]
+	if !ok {
+		return
+	}
+	last := len(g.keyToIndex) - 1
+	g.beginMutation()
+	g.compactDuplicates()
+
+	// Los enlaces entrantes dejan de contar como salientes de su origen
+	for j, from := range g.inLinks[index] {
+		g.numberOutLinks[from] -= 1
+		if g.outLinkWeights != nil {
+			g.outLinkWeights[from] -= g.inLinkWeights[index][j]
+			if g.numberOutLinks[from] == 0 {
+				g.outLinkWeights[from] = 0
+			}
+		}
+	}
+
+	// Quitar los enlaces salientes del nodo y renombrar last -> index
+	for i := range g.inLinks {
+		if i == index {
+			continue
+		}
+		g.removeInLinksFrom(i, index, last)
+	}
+
+	if g.outLinks != nil {
+		for i, outLinks := range g.outLinks {
+			kept := outLinks[:0]
+			for _, link := range outLinks {
+				if link.to == index {
+					continue
+				}
+				if link.to == last {
+					link.to = index
+				}
+				kept = append(kept, link)
+			}
+			g.outLinks[i] = kept
+		}
+	}
+
+	if index != last {
+		lastKey := g.indexToKey[last]
+		g.keyToIndex[lastKey] = index
+		g.indexToKey[index] = lastKey
+
+		g.inLinks[index] = g.inLinks[last]
+		g.numberOutLinks[index] = g.numberOutLinks[last]
+		if g.inLinkWeights != nil {
+			g.inLinkWeights[index] = g.inLinkWeights[last]
+			g.outLinkWeights[index] = g.outLinkWeights[last]
+		}
+		if g.outLinks != nil {
+			g.outLinks[index] = g.outLinks[last]
+		}
+		if last < len(g.lastRanks) {
+			g.lastRanks[index] = g.lastRanks[last]
+		} else if index < len(g.lastRanks) {
+			// El nodo movido es posterior al último resultado: no hereda el
+			// rank del borrado
+			g.lastRanks[index] = 0
+		}
+	}
+
+	// Liberar las referencias del último slot para que el GC las recupere
+	g.inLinks[last] = nil
+	g.inLinks = g.inLinks[:last]
+	g.numberOutLinks = g.numberOutLinks[:last]
+	if g.inLinkWeights != nil {
+		g.inLinkWeights[last] = nil
+		g.inLinkWeights = g.inLinkWeights[:last]
+		g.outLinkWeights = g.outLinkWeights[:last]
+	}
+	if g.outLinks != nil {
+		g.outLinks[last] = nil
+		g.outLinks = g.outLinks[:last]
+	}
+	if last < len(g.lastRanks) {
+		g.lastRanks = g.lastRanks[:last]
+	}
+
+	var noKey K
+	g.indexToKey[last] = noKey
+	g.indexToKey = g.indexToKey[:last]
+	delete(g.keyToIndex, key)
+	g.currentAvailableIndex--
+}
+
+// removeInLinksFrom quita de los enlaces entrantes de i los que vienen de
+// removed y renombra moved -> removed
+func (g *graph[K]) removeInLinksFrom(i, removed, moved int) {
+	inLinks := g.inLinks[i]
+	kept := 0
+
+	for j, from := range inLinks {
+		if from == removed {
+			continue
+		}
+		if from == moved {
+			from = removed
+		}
+		inLinks[kept] = from
+		if g.inLinkWeights != nil {
+			g.inLinkWeights[i][kept] = g.inLinkWeights[i][j]
+		}
+		kept++
+	}
+
+	g.inLinks[i] = inLinks[:kept]
+	if g.inLinkWeights != nil {
+		g.inLinkWeights[i] = g.inLinkWeights[i][:kept]
+	}
+}
+
+func (g *graph[K]) calculateDanglingNodes() []int {
+	danglingNodes := make([]int, 0, len(g.numberOutLinks))
+
+	for i, numberOutLinksForI := range g.numberOutLinks {
+		if numberOutLinksForI == 0 {
+			danglingNodes = append(danglingNodes, i)
+		}
+	}
+
+	return danglingNodes
+}
+
+func (g *graph[K]) Clear() {
+	g.inLinks = [][]int{}
+	g.inLinkWeights = nil
+	g.numberOutLinks = []int{}
+	g.outLinkWeights = nil
+	g.currentAvailableIndex = 0
+	g.keyToIndex = make(map[K]int)
+	g.indexToKey = []K{}
+	g.csr = nil
+	g.frozen = false
+	g.outLinks = nil
+	g.lastRanks = nil
+	g.lastRanksStale = false
+	g.lastOptions = RankOptionsOf[K]{}
+	g.scratch = nil
+	g.duplicatesPending = false
+	g.err = nil
+}
@@ -0,0 +1,135 @@
+package pagerank
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSelfLoop es el error que registra un grafo con RejectSelfLoops al
+// recibir un enlace de un nodo a sí mismo; ver Err.
+var ErrSelfLoop = errors.New("pagerank: self-loop rejected")
+
+// GraphOptions fija cómo trata el grafo los enlaces repetidos y los
+// auto-enlaces. El valor cero conserva el comportamiento original de Link.
+type GraphOptions struct {
+	Duplicates DuplicatePolicy
+	SelfLoops  SelfLoopPolicy
+}
+
+// DuplicatePolicy decide qué pasa cuando se enlaza dos veces el mismo par
+type DuplicatePolicy int
+
+const (
+	// KeepDuplicates cuenta cada repetición como un enlace más (multiplicidad)
+	KeepDuplicates DuplicatePolicy = iota
+	// DedupeDuplicates conserva sólo el primer enlace entre cada par
+	DedupeDuplicates
+	// SumDuplicates fusiona las repeticiones en un enlace cuyo peso es la
+	// suma de sus pesos. Da los mismos ranks que KeepDuplicates con un enlace
+	// por par.
+	SumDuplicates
+)
+
+// SelfLoopPolicy decide qué pasa con un enlace de un nodo a sí mismo
+type SelfLoopPolicy int
+
+const (
+	// KeepSelfLoops acepta los auto-enlaces como cualquier otro enlace
+	KeepSelfLoops SelfLoopPolicy = iota
+	// DropSelfLoops los ignora sin crear el nodo
+	DropSelfLoops
+	// RejectSelfLoops los ignora y registra ErrSelfLoop en Err
+	RejectSelfLoops
+)
+
+// acceptLink aplica la política de auto-enlaces antes de tocar el grafo
+func (g *graph[K]) acceptLink(from, to K) bool {
+	if from != to {
+		return true
+	}
+
+	switch g.options.SelfLoops {
+	case DropSelfLoops:
+		return false
+	case RejectSelfLoops:
+		if g.err == nil {
+			g.err = fmt.Errorf("%w: %v -> %v", ErrSelfLoop, from, to)
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// Err devuelve el primer enlace rechazado por las GraphOptions desde la
+// creación del grafo o el último Clear, o nil si no hubo ninguno
+func (g *graph[K]) Err() error {
+	return g.err
+}
+
+// compactDuplicates aplica DedupeDuplicates o SumDuplicates a los enlaces
+// agregados desde la última compactación. Link sólo marca el grafo y la
+// fusión se hace aquí en una pasada O(nodos + enlaces), recordando para cada
+// origen su posición en la fila que se está recorriendo.
+func (g *graph[K]) compactDuplicates() {
+	if !g.duplicatesPending {
+		return
+	}
+	g.duplicatesPending = false
+
+	size := len(g.inLinks)
+	lastRow := make([]int, size) // fila + 1 en la que se vio cada origen
+	position := make([]int, size)
+	merged := false
+
+	for i := range g.inLinks {
+		inLinks := g.inLinks[i]
+		kept := 0
+
+		for j, from := range inLinks {
+			weight := 1.0
+			if g.inLinkWeights != nil {
+				weight = g.inLinkWeights[i][j]
+			}
+
+			if lastRow[from] != i+1 {
+				lastRow[from] = i + 1
+				position[from] = kept
+				inLinks[kept] = from
+				if g.inLinkWeights != nil {
+					g.inLinkWeights[i][kept] = weight
+				}
+				kept++
+				continue
+			}
+
+			merged = true
+			g.numberOutLinks[from] -= 1
+
+			switch g.options.Duplicates {
+			case SumDuplicates:
+				if g.inLinkWeights == nil {
+					// Todos los enlaces pesan 1 hasta aquí: habilitar pesos
+					// no altera las posiciones ya compactadas
+					g.enableWeights()
+					g.outLinkWeights[from] += 1
+				}
+				g.inLinkWeights[i][position[from]] += weight
+			default:
+				if g.outLinkWeights != nil {
+					g.outLinkWeights[from] -= weight
+				}
+			}
+		}
+
+		g.inLinks[i] = inLinks[:kept]
+		if g.inLinkWeights != nil {
+			g.inLinkWeights[i] = g.inLinkWeights[i][:kept]
+		}
+	}
+
+	// La lista de enlaces salientes de ApplyLinks se reconstruye si hace falta
+	if merged {
+		g.outLinks = nil
+	}
+}
@@ -0,0 +1,149 @@
+package pagerank
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestDedupeDuplicatesShouldKeepASingleLinkPerPair(t *testing.T) {
+	pageRank := NewWithOptions(GraphOptions{Duplicates: DedupeDuplicates})
+	pageRank.Link(0, 1)
+	pageRank.Link(0, 1)
+	pageRank.Link(0, 2)
+	pageRank.Link(0, 1)
+	pageRank.Link(2, 0)
+
+	expected := New()
+	expected.Link(0, 1)
+	expected.Link(0, 2)
+	expected.Link(2, 0)
+
+	assertSameRanks(t, pageRank, expected)
+	assertConsistent(t, &pageRank.graph)
+	assertEqual(t, len(pageRank.inLinks[1]), 1)
+}
+
+func TestDedupeDuplicatesShouldKeepTheFirstWeight(t *testing.T) {
+	pageRank := NewWithOptions(GraphOptions{Duplicates: DedupeDuplicates})
+	pageRank.LinkWeighted(0, 1, 3)
+	pageRank.LinkWeighted(0, 1, 5)
+	pageRank.Link(0, 2)
+
+	expected := New()
+	expected.LinkWeighted(0, 1, 3)
+	expected.Link(0, 2)
+
+	assertSameRanks(t, pageRank, expected)
+	assertEqual(t, pageRank.outLinkWeights[0], 4.0)
+}
+
+func TestSumDuplicatesShouldMergeTheWeights(t *testing.T) {
+	pageRank := NewWithOptions(GraphOptions{Duplicates: SumDuplicates})
+	pageRank.LinkWeighted(0, 1, 2)
+	pageRank.LinkWeighted(0, 1, 3)
+	pageRank.Link(0, 2)
+
+	expected := New()
+	expected.LinkWeighted(0, 1, 5)
+	expected.Link(0, 2)
+
+	assertSameRanks(t, pageRank, expected)
+	assertConsistent(t, &pageRank.graph)
+	assertEqual(t, len(pageRank.inLinks[1]), 1)
+	assertEqual(t, pageRank.inLinkWeights[1][0], 5.0)
+}
+
+func TestSumDuplicatesShouldRankLikeMultiplicity(t *testing.T) {
+	pageRank := NewWithOptions(GraphOptions{Duplicates: SumDuplicates})
+	expected := New()
+	for _, pr := range []Interface{pageRank, expected} {
+		pr.Link(0, 1)
+		pr.Link(0, 2)
+		pr.Link(0, 1)
+		pr.Link(1, 2)
+		pr.Link(1, 2)
+		pr.Link(1, 2)
+	}
+
+	assertSameRanks(t, pageRank, expected)
+	assertConsistent(t, &pageRank.graph)
+	assertEqual(t, len(pageRank.inLinks[2]), 2)
+}
+
+func TestDuplicatePoliciesShouldWorkWithApplyLinks(t *testing.T) {
+	const n = 2000
+	edges := incrementalBatch(n)
+	// Enlaces que ya existen en buildTestGraph y uno repetido dentro del lote
+	edges = append(edges, [2]int{1, 7}, [2]int{1, 7}, [2]int{3, 21})
+
+	for _, policy := range []DuplicatePolicy{DedupeDuplicates, SumDuplicates} {
+		pageRank := NewWithOptions(GraphOptions{Duplicates: policy})
+		buildTestGraph(pageRank, n)
+		pageRank.Rank(0.85, 1e-10, func(_ int, _ float64) {})
+		pageRank.ApplyLinks(edges)
+
+		full := NewWithOptions(GraphOptions{Duplicates: policy})
+		buildTestGraph(full, n)
+		for _, edge := range edges {
+			full.Link(edge[0], edge[1])
+		}
+		expected, _, _ := full.RankContext(context.Background(), RankOptions{FollowingProb: 0.85, Tolerance: 1e-12})
+
+		diff := 0.0
+		for label, rank := range pageRank.CurrentRanks() {
+			diff += math.Abs(rank - expected[label])
+		}
+		if diff > 1e-6 {
+			t.Error(policy, "L1 distance to the full recompute should be below 1e-6 but was", diff)
+		}
+	}
+}
+
+func TestDropSelfLoopsShouldIgnoreThem(t *testing.T) {
+	pageRank := NewWithOptions(GraphOptions{SelfLoops: DropSelfLoops})
+	pageRank.Link(0, 1)
+	pageRank.Link(1, 1)
+	pageRank.LinkWeighted(2, 2, 3)
+
+	expected := New()
+	expected.Link(0, 1)
+
+	assertSameRanks(t, pageRank, expected)
+	assertEqual(t, pageRank.Err(), nil)
+}
+
+func TestRejectSelfLoopsShouldReportAnError(t *testing.T) {
+	pageRank := NewConcurrentWithOptions(4, GraphOptions{SelfLoops: RejectSelfLoops})
+	pageRank.Link(0, 1)
+	assertEqual(t, pageRank.Err(), nil)
+
+	pageRank.Link(1, 1)
+	assert(t, errors.Is(pageRank.Err(), ErrSelfLoop))
+
+	expected := New()
+	expected.Link(0, 1)
+	assertSameRanks(t, pageRank, expected)
+
+	pageRank.Clear()
+	assertEqual(t, pageRank.Err(), nil)
+
+	pageRank.Link(2, 2)
+	assert(t, errors.Is(pageRank.Err(), ErrSelfLoop))
+}
+
+func BenchmarkDedupeDuplicates(b *testing.B) {
+	const n = 200_000
+
+	for i := 0; i < b.N; i++ {
+		pageRank := NewWithOptions(GraphOptions{Duplicates: DedupeDuplicates})
+		for from := 0; from < n; from++ {
+			for j := 0; j < 10; j++ {
+				// la mitad de los enlaces repite un destino, casi todos hacia hubs
+				pageRank.Link(from, (from*7+(j/2)*13)%(n/100))
+			}
+		}
+		pageRank.Freeze()
+	}
+}
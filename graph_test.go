@@ -0,0 +1,185 @@
+package pagerank
+
+import (
+	"math"
+	"testing"
+)
+
+func assertConsistent(t *testing.T, g *graph[int]) {
+	size := len(g.keyToIndex)
+
+	assertEqual(t, len(g.indexToKey), size)
+	assertEqual(t, len(g.inLinks), size)
+	assertEqual(t, len(g.numberOutLinks), size)
+	assertEqual(t, g.currentAvailableIndex, size)
+
+	for key, index := range g.keyToIndex {
+		assertEqual(t, g.indexToKey[index], key)
+	}
+
+	numberOutLinks := make([]int, size)
+	for i, inLinksForI := range g.inLinks {
+		for _, from := range inLinksForI {
+			assert(t, from >= 0 && from < size)
+			numberOutLinks[from]++
+		}
+		if g.inLinkWeights != nil {
+			assertEqual(t, len(g.inLinkWeights[i]), len(inLinksForI))
+		}
+	}
+
+	for i := range numberOutLinks {
+		assertEqual(t, g.numberOutLinks[i], numberOutLinks[i])
+	}
+}
+
+func assertSameRanks(t *testing.T, actual, expected Interface) {
+	expectedRanks := make(map[int]float64)
+	expected.Rank(0.85, 1e-12, func(label int, rank float64) {
+		expectedRanks[label] = rank
+	})
+
+	count := 0
+	actual.Rank(0.85, 1e-12, func(label int, rank float64) {
+		count++
+		if math.Abs(rank-expectedRanks[label]) > 1e-10 {
+			t.Error("Rank for", label, "should be", expectedRanks[label], "but was", rank)
+		}
+	})
+
+	assertEqual(t, count, len(expectedRanks))
+}
+
+func TestUnlinkShouldRemoveTheLink(t *testing.T) {
+	pageRank := New()
+	pageRank.Link(0, 1)
+	pageRank.Link(1, 2)
+	pageRank.Link(0, 2)
+	pageRank.Unlink(1, 2)
+
+	expected := New()
+	expected.Link(0, 1)
+	expected.Link(0, 2)
+
+	assertConsistent(t, &pageRank.graph)
+	assertSameRanks(t, pageRank, expected)
+}
+
+func TestUnlinkShouldRemoveOnlyOneOfTheRepeatedLinks(t *testing.T) {
+	pageRank := New()
+	pageRank.Link(0, 1)
+	pageRank.Link(0, 1)
+	pageRank.Link(0, 2)
+	pageRank.Link(2, 0)
+	pageRank.Unlink(0, 1)
+
+	expected := New()
+	expected.Link(0, 1)
+	expected.Link(0, 2)
+	expected.Link(2, 0)
+
+	assertConsistent(t, &pageRank.graph)
+	assertSameRanks(t, pageRank, expected)
+}
+
+func TestUnlinkOfAnUnknownLinkShouldDoNothing(t *testing.T) {
+	pageRank := New()
+	pageRank.Link(0, 1)
+	pageRank.Unlink(1, 0)
+	pageRank.Unlink(0, 7)
+	pageRank.Unlink(7, 0)
+
+	assertConsistent(t, &pageRank.graph)
+	assertRank(t, pageRank, map[int]float64{0: 35.1, 1: 64.9})
+}
+
+func TestUnlinkShouldKeepTheRemainingWeights(t *testing.T) {
+	pageRank := New()
+	pageRank.LinkWeighted(0, 1, 3)
+	pageRank.LinkWeighted(0, 2, 2)
+	pageRank.LinkWeighted(1, 0, 1)
+	pageRank.LinkWeighted(2, 0, 1)
+	pageRank.Unlink(0, 1)
+
+	expected := New()
+	expected.Link(0, 2)
+	expected.Link(1, 0)
+	expected.Link(2, 0)
+
+	assertConsistent(t, &pageRank.graph)
+	assertSameRanks(t, pageRank, expected)
+}
+
+func TestRemoveNodeShouldRemoveItsLinks(t *testing.T) {
+	for _, removed := range []int{0, 5, 10} {
+		pageRank := NewConcurrentWithWorkers(4)
+		wikipediaExample(pageRank)
+		pageRank.RemoveNode(removed)
+
+		expected := New()
+		for _, link := range [][2]int{
+			{1, 2}, {2, 1}, {3, 0}, {3, 1}, {4, 3},
+			{4, 1}, {4, 5}, {5, 4}, {5, 1}, {6, 1},
+			{6, 4}, {7, 1}, {7, 4}, {8, 1}, {8, 4},
+			{9, 4}, {10, 4},
+		} {
+			if link[0] != removed && link[1] != removed {
+				expected.Link(link[0], link[1])
+			}
+		}
+		//no other node is left without links, so both graphs have the same nodes
+		assertEqual(t, len(pageRank.keyToIndex), 10)
+
+		assertConsistent(t, &pageRank.graph)
+		assertSameRanks(t, pageRank, expected)
+	}
+}
+
+func TestRemoveNodeShouldReuseTheFreedIndices(t *testing.T) {
+	pageRank := New()
+	for i := 0; i < 100; i++ {
+		pageRank.Link(i, (i+1)%100)
+	}
+
+	//every round replaces the nodes whose key is a multiple of 3
+	for round := 0; round < 10; round++ {
+		for i := 0; i < 99; i += 3 {
+			pageRank.RemoveNode(round*1000 + i)
+		}
+		for i := 0; i < 99; i += 3 {
+			pageRank.Link((round+1)*1000+i, i+1)
+			pageRank.Link(i+2, (round+1)*1000+i)
+		}
+		assertConsistent(t, &pageRank.graph)
+		assertEqual(t, len(pageRank.keyToIndex), 100)
+	}
+
+	assertEqual(t, len(pageRank.inLinks), 100)
+}
+
+func TestRemoveNodeShouldKeepWeightsAndOutLinksConsistent(t *testing.T) {
+	pageRank := New()
+	pageRank.LinkWeighted(0, 1, 2)
+	pageRank.LinkWeighted(1, 2, 1)
+	pageRank.LinkWeighted(2, 0, 4)
+	pageRank.LinkWeighted(2, 3, 1)
+	pageRank.LinkWeighted(3, 3, 1)
+	pageRank.Rank(0.85, 1e-10, func(_ int, _ float64) {})
+	pageRank.ApplyLinks([][2]int{{3, 0}})
+
+	pageRank.RemoveNode(1)
+	pageRank.ApplyLinks([][2]int{{0, 2}})
+
+	expected := New()
+	expected.LinkWeighted(2, 0, 4)
+	expected.LinkWeighted(2, 3, 1)
+	expected.LinkWeighted(3, 3, 1)
+	expected.Link(3, 0)
+	expected.Link(0, 2)
+
+	assertConsistent(t, &pageRank.graph)
+	for from, outLinks := range pageRank.outLinks {
+		assertEqual(t, len(outLinks), pageRank.numberOutLinks[from])
+	}
+	assertSameRanks(t, pageRank, expected)
+}
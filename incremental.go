@@ -0,0 +1,236 @@
+package pagerank
+
+import (
+	"context"
+	"math"
+)
+
+// outLink es un enlace saliente, usado para propagar residuos hacia adelante
+type outLink struct {
+	to     int
+	weight float64
+}
+
+// buildOutLinks construye la lista de enlaces salientes a partir de inLinks.
+// Una vez creada, linkWithIndices la mantiene al día.
+func (g *graph[K]) buildOutLinks() {
+	g.outLinks = make([][]outLink, len(g.numberOutLinks))
+
+	for i, numberOutLinksForI := range g.numberOutLinks {
+		g.outLinks[i] = make([]outLink, 0, numberOutLinksForI)
+	}
+
+	for to, inLinksForI := range g.inLinks {
+		for j, from := range inLinksForI {
+			weight := 1.0
+			if g.inLinkWeights != nil {
+				weight = g.inLinkWeights[to][j]
+			}
+			g.outLinks[from] = append(g.outLinks[from], outLink{to: to, weight: weight})
+		}
+	}
+}
+
+func (g *graph[K]) updateOutLinks(fromAsIndex, toAsIndex int, weight float64) {
+	missingSlots := len(g.keyToIndex) - len(g.outLinks)
+
+	if missingSlots > 0 {
+		g.outLinks = append(g.outLinks, make([][]outLink, missingSlots)...)
+	}
+
+	g.outLinks[fromAsIndex] = append(g.outLinks[fromAsIndex], outLink{to: toAsIndex, weight: weight})
+}
+
+// hasOutLink indica si ya existe un enlace de from a to
+func (g *graph[K]) hasOutLink(fromAsIndex, toAsIndex int) bool {
+	if fromAsIndex >= len(g.outLinks) {
+		return false
+	}
+	for _, link := range g.outLinks[fromAsIndex] {
+		if link.to == toAsIndex {
+			return true
+		}
+	}
+	return false
+}
+
+// outWeight devuelve el peso saliente total del nodo, 0 si no existía
+func (g *graph[K]) outWeight(index int) float64 {
+	if index >= len(g.numberOutLinks) {
+		return 0
+	}
+	if g.outLinkWeights != nil {
+		return g.outLinkWeights[index]
+	}
+	return float64(g.numberOutLinks[index])
+}
+
+// applyLinks agrega los enlaces y actualiza el último resultado propagando
+// sólo los residuos que generan (push hacia adelante), en lugar de repetir la
+// iteración de potencias completa.
+//
+// Sea x el resultado anterior. Tras el lote, el residuo de cada nodo respecto
+// al nuevo punto fijo sólo es distinto de cero en los destinos de las fuentes
+// cuyo grado de salida cambió y en los nodos nuevos, salvo un término uniforme
+// (cambios de N y de la masa colgante). Como el salto y la masa colgante son
+// uniformes, ese término sólo escala la solución, así que se descarta y se
+// renormaliza al final. Por la misma razón la masa que se empuja desde nodos
+// colgantes también se descarta.
+//
+// Si no hay un resultado previo al día, o se calculó con personalización o con
+// otra estrategia para los nodos colgantes, o sin Tolerance que acote los
+// residuos (p. ej. con un ConvergenceCriterion propio), se recurre a
+// RankContext con arranque en caliente.
+func (g *graph[K]) applyLinks(e engine, edges [][2]K) {
+	if g.lastRanks == nil {
+		for _, edge := range edges {
+			g.Link(edge[0], edge[1])
+		}
+		return
+	}
+
+	if g.lastRanksStale || len(g.lastRanks) == 0 || g.lastOptions.Personalization != nil ||
+		g.lastOptions.danglingStrategy() != DanglingUniform || g.lastOptions.Tolerance <= 0 ||
+		g.lastOptions.Convergence != nil {
+		for _, edge := range edges {
+			g.Link(edge[0], edge[1])
+		}
+
+		opts := g.lastOptions
+		opts.Initial = nil
+		opts.WarmStart = true
+		// Un resultado que no convergió (p. ej. por MaxIterations) no queda
+		// al día: el próximo lote vuelve a recalcular
+		if _, _, err := g.rank(context.Background(), e, opts, nil); err != nil {
+			g.lastRanksStale = true
+		}
+		return
+	}
+
+	g.beginMutation()
+	g.compactDuplicates()
+	if g.outLinks == nil {
+		g.buildOutLinks()
+	}
+
+	followingProb := g.lastOptions.FollowingProb
+	oldSize := len(g.lastRanks)
+
+	danglingMass := 0.0
+	for _, danglingNode := range g.calculateDanglingNodes() {
+		danglingMass += g.lastRanks[danglingNode]
+	}
+
+	// Estado de cada fuente antes del lote: los enlaces nuevos quedan al
+	// final de su lista de salida
+	type sourceBefore struct {
+		outLinks  int
+		outWeight float64
+	}
+	sources := make(map[int]sourceBefore)
+
+	for _, edge := range edges {
+		if !g.acceptLink(edge[0], edge[1]) {
+			continue
+		}
+
+		fromAsIndex := g.keyAsArrayIndex(edge[0])
+		toAsIndex := g.keyAsArrayIndex(edge[1])
+		if g.options.Duplicates == DedupeDuplicates && g.hasOutLink(fromAsIndex, toAsIndex) {
+			continue
+		}
+
+		if _, ok := sources[fromAsIndex]; !ok {
+			before := sourceBefore{outWeight: g.outWeight(fromAsIndex)}
+			if fromAsIndex < len(g.outLinks) {
+				before.outLinks = len(g.outLinks[fromAsIndex])
+			}
+			sources[fromAsIndex] = before
+		}
+
+		g.linkWithIndices(fromAsIndex, toAsIndex, 1.0)
+	}
+
+	size := len(g.keyToIndex)
+	x := append(g.lastRanks, make([]float64, size-oldSize)...)
+	r := make([]float64, size)
+
+	// Los nodos nuevos no recibían nada: les falta el salto y la masa colgante
+	newNodeResidual := ((1.0 - followingProb) + followingProb*danglingMass) / float64(oldSize)
+	for i := oldSize; i < size; i++ {
+		r[i] = newNodeResidual
+	}
+
+	for from, before := range sources {
+		xFrom := x[from]
+		if xFrom == 0 {
+			continue
+		}
+
+		inverseOutWeight := 1.0 / g.outWeight(from)
+		for k, link := range g.outLinks[from] {
+			share := link.weight * inverseOutWeight
+			if k < before.outLinks {
+				share -= link.weight / before.outWeight
+			}
+			r[link.to] += followingProb * xFrom * share
+		}
+	}
+
+	epsilon := g.lastOptions.Tolerance / float64(size)
+	queued := make([]bool, size)
+	queue := make([]int, 0)
+
+	for i, rForI := range r {
+		if math.Abs(rForI) > epsilon {
+			queued[i] = true
+			queue = append(queue, i)
+		}
+	}
+
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		queued[u] = false
+
+		rForU := r[u]
+		r[u] = 0
+		x[u] += rForU
+
+		if g.numberOutLinks[u] == 0 {
+			continue
+		}
+
+		scaled := followingProb * rForU / g.outWeight(u)
+		for _, link := range g.outLinks[u] {
+			r[link.to] += scaled * link.weight
+			if !queued[link.to] && math.Abs(r[link.to]) > epsilon {
+				queued[link.to] = true
+				queue = append(queue, link.to)
+			}
+		}
+	}
+
+	sum := 0.0
+	for _, xForI := range x {
+		sum += xForI
+	}
+
+	inverseOfSum := 1.0 / sum
+	for i := range x {
+		x[i] *= inverseOfSum
+	}
+
+	g.lastRanks = x
+	g.lastRanksStale = false
+}
+
+// CurrentRanks devuelve el último resultado mantenido por Rank o ApplyLinks,
+// o nil si todavía no se ha calculado ninguno. Si ese cálculo terminó con
+// error es el último vector alcanzado, no uno convergido.
+func (g *graph[K]) CurrentRanks() map[K]float64 {
+	if g.lastRanks == nil {
+		return nil
+	}
+	return g.rankMap(g.lastRanks)
+}
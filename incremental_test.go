@@ -0,0 +1,154 @@
+package pagerank
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func testEdges(n int) [][2]int {
+	edges := make([][2]int, 0, 4*n)
+	for i := 0; i < n; i++ {
+		//every 10th node is dangling
+		if i%10 == 0 {
+			continue
+		}
+		for j := 0; j < 4; j++ {
+			edges = append(edges, [2]int{i, (i*7 + j*13 + j*j) % n})
+		}
+	}
+	return edges
+}
+
+func buildTestGraph(pageRank Interface, n int) {
+	for _, edge := range testEdges(n) {
+		pageRank.Link(edge[0], edge[1])
+	}
+}
+
+func incrementalBatch(n int) [][2]int {
+	edges := make([][2]int, 0)
+	for i := 0; i < 50; i++ {
+		edges = append(edges, [2]int{(i * 37) % n, (i*91 + 5) % n})
+	}
+	//new nodes and an edge out of a dangling node
+	edges = append(edges, [2]int{n, 1}, [2]int{2, n + 1}, [2]int{n + 1, n}, [2]int{10, 3})
+	return edges
+}
+
+func assertCloseToFullRecompute(t *testing.T, actual map[int]float64, n int, edges [][2]int, tolerance float64) {
+	full := New()
+	buildTestGraph(full, n)
+	for _, edge := range edges {
+		full.Link(edge[0], edge[1])
+	}
+
+	expected := make(map[int]float64)
+	full.Rank(0.85, 1e-12, func(label int, rank float64) {
+		expected[label] = rank
+	})
+
+	assertEqual(t, len(actual), len(expected))
+
+	diff := 0.0
+	for label, rank := range expected {
+		diff += math.Abs(rank - actual[label])
+	}
+
+	if diff > tolerance {
+		t.Error("L1 distance to the full recompute should be below", tolerance, "but was", diff)
+	}
+}
+
+func TestApplyLinksShouldMatchAFullRecompute(t *testing.T) {
+	const n = 2000
+	edges := incrementalBatch(n)
+
+	for _, pageRank := range []Interface{New(), NewConcurrentWithWorkers(4)} {
+		buildTestGraph(pageRank, n)
+		pageRank.Rank(0.85, 1e-10, func(_ int, _ float64) {})
+
+		pageRank.ApplyLinks(edges[:20])
+		pageRank.ApplyLinks(edges[20:])
+
+		assertCloseToFullRecompute(t, pageRank.CurrentRanks(), n, edges, 1e-6)
+	}
+}
+
+func TestApplyLinksShouldHandleWeightedGraphs(t *testing.T) {
+	pageRank := New()
+	pageRank.LinkWeighted(0, 1, 3)
+	pageRank.LinkWeighted(0, 2, 1)
+	pageRank.LinkWeighted(1, 2, 2)
+	pageRank.LinkWeighted(2, 0, 1)
+	pageRank.Rank(0.85, 1e-12, func(_ int, _ float64) {})
+
+	pageRank.ApplyLinks([][2]int{{1, 0}, {2, 3}, {3, 1}})
+
+	full := New()
+	full.LinkWeighted(0, 1, 3)
+	full.LinkWeighted(0, 2, 1)
+	full.LinkWeighted(1, 2, 2)
+	full.LinkWeighted(2, 0, 1)
+	full.Link(1, 0)
+	full.Link(2, 3)
+	full.Link(3, 1)
+
+	actual := pageRank.CurrentRanks()
+	full.Rank(0.85, 1e-12, func(label int, rank float64) {
+		if math.Abs(rank-actual[label]) > 1e-9 {
+			t.Error("Rank for", label, "should be", rank, "but was", actual[label])
+		}
+	})
+}
+
+func TestApplyLinksWithoutAPreviousRankShouldOnlyLink(t *testing.T) {
+	pageRank := New()
+	pageRank.ApplyLinks([][2]int{{0, 1}})
+
+	assert(t, pageRank.CurrentRanks() == nil)
+	assertRank(t, pageRank, map[int]float64{0: 35.1, 1: 64.9})
+}
+
+func TestApplyLinksShouldRecomputeWhenTheGraphChangedOutsideOfIt(t *testing.T) {
+	const n = 500
+	edges := incrementalBatch(n)
+
+	pageRank := New()
+	buildTestGraph(pageRank, n)
+	pageRank.Rank(0.85, 1e-10, func(_ int, _ float64) {})
+
+	pageRank.Link(edges[0][0], edges[0][1])
+	pageRank.ApplyLinks(edges[1:])
+
+	assertCloseToFullRecompute(t, pageRank.CurrentRanks(), n, edges, 1e-6)
+}
+
+func TestApplyLinksShouldRecomputeWithACustomConvergence(t *testing.T) {
+	const n = 500
+	edges := incrementalBatch(n)
+
+	pageRank := New()
+	buildTestGraph(pageRank, n)
+	pageRank.RankContext(context.Background(), RankOptions{FollowingProb: 0.85, Tolerance: 1e-10, Convergence: L1Norm(1e-10)})
+
+	pageRank.ApplyLinks(edges)
+
+	//the incremental path would have built the out-links
+	assert(t, pageRank.outLinks == nil)
+	assertCloseToFullRecompute(t, pageRank.CurrentRanks(), n, edges, 1e-6)
+}
+
+func TestApplyLinksShouldNotAcceptAFailedRecompute(t *testing.T) {
+	const n = 500
+
+	pageRank := New()
+	buildTestGraph(pageRank, n)
+	opts := RankOptions{FollowingProb: 0.85, Tolerance: 1e-10, MaxIterations: 3}
+	pageRank.RankContext(context.Background(), opts)
+
+	pageRank.ApplyLinks(incrementalBatch(n))
+
+	assert(t, pageRank.lastRanksStale)
+	assertEqual(t, pageRank.lastOptions.MaxIterations, 3)
+}
@@ -1,30 +1,72 @@
 package pagerank
 
 import (
+	"context"
 	"fmt"
-	"math"
+	"iter"
 )
 
-type Interface interface {
-	Rank(followingProb, tolerance float64, resultFunc func(label int, rank float64))
-	Link(from, to int)
+// Graph es un grafo de PageRank cuyos nodos se identifican con claves de
+// cualquier tipo comparable (por ejemplo string o un UUID [16]byte).
+// Internamente las claves se traducen a índices de arreglo. Los cálculos de
+// rank se pueden pedir desde varias goroutines a la vez (se ejecutan de a
+// uno, porque comparten búferes y el último resultado); modificar el grafo
+// mientras tanto no es seguro.
+type Graph[K comparable] interface {
+	Rank(followingProb, tolerance float64, resultFunc func(label K, rank float64)) RankStats
+	RankPersonalized(followingProb, tolerance float64, personalization map[K]float64, personalizedDangling bool, resultFunc func(label K, rank float64)) RankStats
+	RankContext(ctx context.Context, opts RankOptionsOf[K]) (map[K]float64, RankStats, error)
+	RankResults(ctx context.Context, opts RankOptionsOf[K]) (*Ranks[K], RankStats, error)
+	RankSeq(followingProb, tolerance float64) (iter.Seq2[K, float64], RankStats)
+	RankInto(ctx context.Context, dst []float64, opts RankOptionsOf[K]) ([]float64, RankStats, error)
+	Key(index int) K
+	Link(from, to K)
+	LinkWeighted(from, to K, weight float64)
+	AddEdges(edges [][2]K)
+	LoadEdges(edges iter.Seq2[K, K], hint LoadHint)
+	Unlink(from, to K)
+	RemoveNode(key K)
+	Freeze()
+	Reorder(ordering Ordering)
+	ApplyLinks(edges [][2]K)
+	CurrentRanks() map[K]float64
+	Err() error
 }
 
-type pageRank struct {
-	inLinks               [][]int
-	numberOutLinks        []int
-	currentAvailableIndex int
-	keyToIndex            map[int]int
-	indexToKey            map[int]int
+// Interface es el grafo con claves int, la API original de la librería
+type Interface = Graph[int]
+
+type pageRank[K comparable] struct {
+	graph[K]
+	pool    *workerPool   // sólo en modo determinista, con un único worker
+	current engineOptions // opciones del cálculo en curso
+}
+
+func New() *pageRank[int] {
+	return NewGraph[int]()
+}
+
+// NewWithOptions crea un grafo secuencial con claves int y las políticas de
+// enlaces de opts
+func NewWithOptions(opts GraphOptions) *pageRank[int] {
+	return NewGraphWithOptions[int](opts)
 }
 
-func New() *pageRank {
-	pr := new(pageRank)
+// NewGraph crea un grafo secuencial con claves de tipo K
+func NewGraph[K comparable]() *pageRank[K] {
+	return NewGraphWithOptions[K](GraphOptions{})
+}
+
+// NewGraphWithOptions crea un grafo secuencial con claves de tipo K y las
+// políticas de enlaces de opts
+func NewGraphWithOptions[K comparable](opts GraphOptions) *pageRank[K] {
+	pr := new(pageRank[K])
+	pr.options = opts
 	pr.Clear()
 	return pr
 }
 
-func (pr *pageRank) String() string {
+func (pr *pageRank[K]) String() string {
 	return fmt.Sprintf(
 		"PageRank Struct:\n"+
 			"InLinks: %v\n"+
@@ -40,132 +82,147 @@ func (pr *pageRank) String() string {
 	)
 }
 
-func (pr *pageRank) keyAsArrayIndex(key int) int {
-	index, ok := pr.keyToIndex[key]
-
-	if !ok {
-		index = pr.currentAvailableIndex
-		pr.currentAvailableIndex++
-		pr.keyToIndex[key] = index
-		pr.indexToKey[index] = key
+// start no reserva nada: el motor secuencial itera en el hilo que llama. En
+// modo determinista recorre los mismos bloques fijos que el concurrente, con
+// un pool de un solo worker que no arranca goroutines.
+func (pr *pageRank[K]) start(c *csr, options engineOptions) func(stats *RankStats) {
+	pr.current = options
+	if !options.deterministic {
+		return func(stats *RankStats) {}
 	}
 
-	return index
-}
-
-func (pr *pageRank) updateInLinks(fromAsIndex, toAsIndex int) {
-	missingSlots := len(pr.keyToIndex) - len(pr.inLinks)
-
-	if missingSlots > 0 {
-		pr.inLinks = append(pr.inLinks, make([][]int, missingSlots)...)
+	pr.pool = schedulePool(c, []workChunk{{start: 0, end: c.size()}}, options)
+	return func(stats *RankStats) {
+		pr.pool = nil
 	}
-
-	pr.inLinks[toAsIndex] = append(pr.inLinks[toAsIndex], fromAsIndex)
 }
 
-func (pr *pageRank) updateNumberOutLinks(fromAsIndex int) {
-	missingSlots := len(pr.keyToIndex) - len(pr.numberOutLinks)
-
-	if missingSlots > 0 {
-		pr.numberOutLinks = append(pr.numberOutLinks, make([]int, missingSlots)...)
+func (pr *pageRank[K]) step(followingProb float64, t teleport, c *csr, p, v []float64) Change {
+	if pr.pool != nil {
+		return pr.pool.iterate(phasePower, followingProb, 1.0, t, p, v)
 	}
-
-	pr.numberOutLinks[fromAsIndex] += 1
+	if pr.current.kernel == PushKernel {
+		return sequentialPushStep(followingProb, t, c, p, v, pr.current)
+	}
+	return sequentialStep(followingProb, t, c, p, v, pr.current)
 }
 
-func (pr *pageRank) linkWithIndices(fromAsIndex, toAsIndex int) {
-	pr.updateInLinks(fromAsIndex, toAsIndex)
-	pr.updateNumberOutLinks(fromAsIndex)
+func (pr *pageRank[K]) gaussSeidelStep(followingProb, omega float64, t teleport, c *csr, p, v []float64) Change {
+	if pr.pool != nil {
+		return pr.pool.iterate(phaseGaussSeidel, followingProb, omega, t, p, v)
+	}
+	return sequentialGaussSeidelStep(followingProb, omega, t, c, p, v, pr.current)
 }
 
-func (pr *pageRank) Link(from, to int) {
-	fromAsIndex := pr.keyAsArrayIndex(from)
-	toAsIndex := pr.keyAsArrayIndex(to)
-
-	pr.linkWithIndices(fromAsIndex, toAsIndex)
-}
+// sequentialStep es un paso de la iteración de potencias. Los nodos
+// congelados por Adaptive conservan su valor sin recorrer sus enlaces.
+func sequentialStep(followingProb float64, t teleport, c *csr, p, v []float64, options engineOptions) Change {
+	innerProduct := danglingMass(p, c.danglingNodes, options.compensated)
 
-func (pr *pageRank) calculateDanglingNodes() []int {
-	danglingNodes := make([]int, 0, len(pr.numberOutLinks))
+	vsum := accumulator{compensated: options.compensated}
 
-	for i, numberOutLinksForI := range pr.numberOutLinks {
-		if numberOutLinksForI == 0 {
-			danglingNodes = append(danglingNodes, i)
+	for i := range v {
+		if options.frozen != nil && options.frozen[i] {
+			v[i] = p[i]
+		} else {
+			ksum := c.rankIn(i, p, options.compensated)
+			v[i] = followingProb*(ksum+t.danglingTerm(i, innerProduct, c, p)) + (1.0-followingProb)*t.at(i)
 		}
+		vsum.add(v[i])
 	}
 
-	return danglingNodes
+	acc := normalizeWithChange(p, v, 1.0/vsum.value())
+	return acc.change(p, v)
 }
 
-func (pr *pageRank) step(followingProb, tOverSize float64, p []float64, danglingNodes []int) []float64 {
-	innerProduct := 0.0
+// sequentialGaussSeidelStep hace un barrido Gauss-Seidel/SOR: a diferencia de
+// step, cada nodo ya ve los valores nuevos de los nodos con índice menor
+func sequentialGaussSeidelStep(followingProb, omega float64, t teleport, c *csr, p, v []float64, options engineOptions) Change {
+	innerProduct := danglingMass(p, c.danglingNodes, options.compensated)
 
-	for _, danglingNode := range danglingNodes {
-		innerProduct += p[danglingNode]
-	}
-
-	innerProductOverSize := innerProduct / float64(len(p))
-	vsum := 0.0
-	v := make([]float64, len(p))
-
-	for i, inLinksForI := range pr.inLinks {
-		ksum := 0.0
+	vsum := accumulator{compensated: options.compensated}
+	copy(v, p)
 
-		for _, index := range inLinksForI {
-			ksum += p[index] / float64(pr.numberOutLinks[index])
+	for i := range v {
+		if options.frozen != nil && options.frozen[i] {
+			vsum.add(v[i])
+			continue
 		}
-
-		v[i] = followingProb*(ksum+innerProductOverSize) + tOverSize
-		vsum += v[i]
+		ksum := c.rankIn(i, v, options.compensated)
+		gs := followingProb*(ksum+t.danglingTerm(i, innerProduct, c, v)) + (1.0-followingProb)*t.at(i)
+		v[i] = (1.0-omega)*v[i] + omega*gs
+		vsum.add(v[i])
 	}
 
-	inverseOfSum := 1.0 / vsum
+	acc := normalizeWithChange(p, v, 1.0/vsum.value())
+	return acc.change(p, v)
+}
+
+// normalizeWithChange multiplica v por inverseOfSum y en la misma pasada
+// acumula su cambio respecto de p, para no recorrer la memoria dos veces
+func normalizeWithChange(p, v []float64, inverseOfSum float64) changeAccumulator {
+	var acc changeAccumulator
 
 	for i := range v {
 		v[i] *= inverseOfSum
+		acc.add(p[i], v[i])
 	}
 
-	return v
+	return acc
 }
 
-func calculateChange(p, new_p []float64) float64 {
-	acc := 0.0
-
-	for i, pForI := range p {
-		acc += math.Abs(pForI - new_p[i])
-	}
-
-	return acc
+func (pr *pageRank[K]) Rank(followingProb, tolerance float64, resultFunc func(label K, rank float64)) RankStats {
+	return pr.RankPersonalized(followingProb, tolerance, nil, false, resultFunc)
 }
 
-func (pr *pageRank) Rank(followingProb, tolerance float64, resultFunc func(label int, rank float64)) {
-	size := len(pr.keyToIndex)
-	inverseOfSize := 1.0 / float64(size)
-	tOverSize := (1.0 - followingProb) / float64(size)
-	danglingNodes := pr.calculateDanglingNodes()
+// RankPersonalized calcula el PageRank personalizado: el navegante salta a los
+// nodos en proporción a los pesos de personalization en lugar de hacerlo de
+// forma uniforme. Si personalizedDangling es true, la masa de los nodos
+// colgantes también se reparte según esos pesos.
+func (pr *pageRank[K]) RankPersonalized(followingProb, tolerance float64, personalization map[K]float64, personalizedDangling bool, resultFunc func(label K, rank float64)) RankStats {
+	p, stats, _ := pr.rank(context.Background(), pr, RankOptionsOf[K]{
+		FollowingProb:        followingProb,
+		Tolerance:            tolerance,
+		Personalization:      personalization,
+		PersonalizedDangling: personalizedDangling,
+	}, nil)
+	pr.emit(p, resultFunc)
+	return stats
+}
 
-	p := make([]float64, size)
-	for i := range p {
-		p[i] = inverseOfSize
-	}
+// RankContext calcula el PageRank según opts respetando la cancelación de ctx.
+// Si ctx se cancela, se agota MaxIterations o el cambio da NaN devuelve el
+// error correspondiente junto con el mejor vector calculado hasta ese momento
+// y sus estadísticas.
+func (pr *pageRank[K]) RankContext(ctx context.Context, opts RankOptionsOf[K]) (map[K]float64, RankStats, error) {
+	p, stats, err := pr.rank(ctx, pr, opts, nil)
+	return pr.rankMap(p), stats, err
+}
 
-	change := 2.0
+// RankResults es RankContext con el resultado como Ranks, que permite
+// consultar el top-K, el orden completo y percentiles sin armar un mapa.
+func (pr *pageRank[K]) RankResults(ctx context.Context, opts RankOptionsOf[K]) (*Ranks[K], RankStats, error) {
+	return pr.rankResults(ctx, pr, opts)
+}
 
-	for change > tolerance {
-		new_p := pr.step(followingProb, tOverSize, p, danglingNodes)
-		change = calculateChange(p, new_p)
-		p = new_p
-	}
+// RankSeq es Rank con el resultado como iterador:
+//
+//	ranks, _ := graph.RankSeq(0.85, 0.0001)
+//	for key, rank := range ranks { ... }
+func (pr *pageRank[K]) RankSeq(followingProb, tolerance float64) (iter.Seq2[K, float64], RankStats) {
+	return pr.rankSeq(pr, RankOptionsOf[K]{FollowingProb: followingProb, Tolerance: tolerance})
+}
 
-	for i, pForI := range p {
-		resultFunc(pr.indexToKey[i], pForI)
-	}
+// RankInto es RankContext sin reservar memoria para el resultado: lo escribe
+// en dst (agrandándolo sólo si le falta capacidad) indexado por el índice
+// interno de cada nodo, que Key traduce a su clave. Reutilizando dst entre
+// llamadas, recalcular el rank no reserva memoria por vector.
+func (pr *pageRank[K]) RankInto(ctx context.Context, dst []float64, opts RankOptionsOf[K]) ([]float64, RankStats, error) {
+	return pr.rank(ctx, pr, opts, dst)
 }
 
-func (pr *pageRank) Clear() {
-	pr.inLinks = [][]int{}
-	pr.numberOutLinks = []int{}
-	pr.currentAvailableIndex = 0
-	pr.keyToIndex = make(map[int]int)
-	pr.indexToKey = make(map[int]int)
+// ApplyLinks agrega un lote de enlaces y actualiza el último resultado de forma
+// incremental; ver CurrentRanks.
+func (pr *pageRank[K]) ApplyLinks(edges [][2]K) {
+	pr.applyLinks(pr, edges)
 }
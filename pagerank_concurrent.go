@@ -1,23 +1,21 @@
 package pagerank
 
 import (
+	"context"
 	"fmt"
-	"math"
+	"iter"
 	"runtime"
-	"sync"
 )
 
 // Umbral mínimo de elementos para justificar paralelización
 // Por debajo de este valor, el overhead de goroutines supera el beneficio
 const parallelizationThreshold = 5000
 
-type pageRankConcurrent struct {
-	inLinks               [][]int
-	numberOutLinks        []int
-	currentAvailableIndex int
-	keyToIndex            map[int]int
-	indexToKey            map[int]int
-	numWorkers            int
+type pageRankConcurrent[K comparable] struct {
+	graph[K]
+	numWorkers int
+	pool       *workerPool   // sólo existe mientras dura un cálculo
+	current    engineOptions // opciones del cálculo en curso
 }
 
 // workChunk representa un rango de trabajo para un worker
@@ -27,7 +25,7 @@ type workChunk struct {
 }
 
 // calculateWorkChunks divide el trabajo en chunks balanceados para los workers
-func (pr *pageRankConcurrent) calculateWorkChunks(totalSize int) ([]workChunk, int) {
+func (pr *pageRankConcurrent[K]) calculateWorkChunks(totalSize int) ([]workChunk, int) {
 	if totalSize < parallelizationThreshold {
 		// No paralelizar si es muy pequeño
 		return []workChunk{{start: 0, end: totalSize}}, 1
@@ -55,21 +53,42 @@ func (pr *pageRankConcurrent) calculateWorkChunks(totalSize int) ([]workChunk, i
 	return chunks, numWorkers
 }
 
-func NewConcurrent() *pageRankConcurrent {
-	pr := new(pageRankConcurrent)
-	pr.numWorkers = runtime.NumCPU()
-	pr.Clear()
-	return pr
+func NewConcurrent() *pageRankConcurrent[int] {
+	return NewGraphConcurrent[int]()
+}
+
+func NewConcurrentWithWorkers(numWorkers int) *pageRankConcurrent[int] {
+	return NewGraphConcurrentWithWorkers[int](numWorkers)
+}
+
+// NewGraphConcurrent crea un grafo concurrente con claves de tipo K y un
+// worker por CPU
+func NewGraphConcurrent[K comparable]() *pageRankConcurrent[K] {
+	return NewGraphConcurrentWithWorkers[K](runtime.NumCPU())
+}
+
+// NewConcurrentWithOptions crea un grafo concurrente con claves int y las
+// políticas de enlaces de opts
+func NewConcurrentWithOptions(numWorkers int, opts GraphOptions) *pageRankConcurrent[int] {
+	return NewGraphConcurrentWithOptions[int](numWorkers, opts)
+}
+
+// NewGraphConcurrentWithWorkers crea un grafo concurrente con claves de tipo K
+func NewGraphConcurrentWithWorkers[K comparable](numWorkers int) *pageRankConcurrent[K] {
+	return NewGraphConcurrentWithOptions[K](numWorkers, GraphOptions{})
 }
 
-func NewConcurrentWithWorkers(numWorkers int) *pageRankConcurrent {
-	pr := new(pageRankConcurrent)
+// NewGraphConcurrentWithOptions crea un grafo concurrente con claves de tipo
+// K y las políticas de enlaces de opts
+func NewGraphConcurrentWithOptions[K comparable](numWorkers int, opts GraphOptions) *pageRankConcurrent[K] {
+	pr := new(pageRankConcurrent[K])
 	pr.numWorkers = numWorkers
+	pr.options = opts
 	pr.Clear()
 	return pr
 }
 
-func (pr *pageRankConcurrent) String() string {
+func (pr *pageRankConcurrent[K]) String() string {
 	return fmt.Sprintf(
 		"PageRank Concurrent Struct:\n"+
 			"InLinks: %v\n"+
@@ -87,267 +106,86 @@ func (pr *pageRankConcurrent) String() string {
 	)
 }
 
-func (pr *pageRankConcurrent) keyAsArrayIndex(key int) int {
-	index, ok := pr.keyToIndex[key]
-
-	if !ok {
-		index = pr.currentAvailableIndex
-		pr.currentAvailableIndex++
-		pr.keyToIndex[key] = index
-		pr.indexToKey[index] = key
+// start crea el pool de workers del cálculo con el reparto pedido. Los
+// grafos por debajo de parallelizationThreshold iteran secuencialmente, sin
+// pool, salvo en modo determinista: ahí un pool de un worker usa los mismos
+// bloques que cualquier otro.
+func (pr *pageRankConcurrent[K]) start(c *csr, options engineOptions) func(stats *RankStats) {
+	pr.current = options
+	chunks, numWorkers := pr.calculateWorkChunks(c.size())
+	if numWorkers == 1 && !options.deterministic {
+		return func(stats *RankStats) {}
 	}
 
-	return index
-}
-
-func (pr *pageRankConcurrent) updateInLinks(fromAsIndex, toAsIndex int) {
-	missingSlots := len(pr.keyToIndex) - len(pr.inLinks)
+	pr.pool = schedulePool(c, chunks, options)
 
-	if missingSlots > 0 {
-		pr.inLinks = append(pr.inLinks, make([][]int, missingSlots)...)
+	return func(stats *RankStats) {
+		stats.WorkerLoads = pr.pool.loads()
+		pr.pool.close()
+		pr.pool = nil
 	}
-
-	pr.inLinks[toAsIndex] = append(pr.inLinks[toAsIndex], fromAsIndex)
 }
 
-func (pr *pageRankConcurrent) updateNumberOutLinks(fromAsIndex int) {
-	missingSlots := len(pr.keyToIndex) - len(pr.numberOutLinks)
-
-	if missingSlots > 0 {
-		pr.numberOutLinks = append(pr.numberOutLinks, make([]int, missingSlots)...)
+// Calcula el vector v de forma concurrente - AQUÍ ESTÁ EL MAYOR BENEFICIO
+// (la forma CSR trae 1/numberOutLinks precalculado para evitar divisiones repetidas)
+func (pr *pageRankConcurrent[K]) step(followingProb float64, t teleport, c *csr, p, v []float64) Change {
+	if pr.pool == nil {
+		if pr.current.kernel == PushKernel {
+			return sequentialPushStep(followingProb, t, c, p, v, pr.current)
+		}
+		return sequentialStep(followingProb, t, c, p, v, pr.current)
 	}
-
-	pr.numberOutLinks[fromAsIndex] += 1
+	return pr.pool.iterate(phasePower, followingProb, 1.0, t, p, v)
 }
 
-func (pr *pageRankConcurrent) linkWithIndices(fromAsIndex, toAsIndex int) {
-	pr.updateInLinks(fromAsIndex, toAsIndex)
-	pr.updateNumberOutLinks(fromAsIndex)
+// Barrido Gauss-Seidel/SOR por bloques: cada worker recorre su chunk en orden
+// usando sus propios valores nuevos y, para los nodos de otros chunks, los
+// de la iteración anterior
+func (pr *pageRankConcurrent[K]) gaussSeidelStep(followingProb, omega float64, t teleport, c *csr, p, v []float64) Change {
+	if pr.pool == nil {
+		return sequentialGaussSeidelStep(followingProb, omega, t, c, p, v, pr.current)
+	}
+	return pr.pool.iterate(phaseGaussSeidel, followingProb, omega, t, p, v)
 }
 
-func (pr *pageRankConcurrent) Link(from, to int) {
-	fromAsIndex := pr.keyAsArrayIndex(from)
-	toAsIndex := pr.keyAsArrayIndex(to)
-
-	pr.linkWithIndices(fromAsIndex, toAsIndex)
+func (pr *pageRankConcurrent[K]) Rank(followingProb, tolerance float64, resultFunc func(label K, rank float64)) RankStats {
+	return pr.RankPersonalized(followingProb, tolerance, nil, false, resultFunc)
 }
 
-func (pr *pageRankConcurrent) calculateDanglingNodes() []int {
-	danglingNodes := make([]int, 0, len(pr.numberOutLinks))
-
-	for i, numberOutLinksForI := range pr.numberOutLinks {
-		if numberOutLinksForI == 0 {
-			danglingNodes = append(danglingNodes, i)
-		}
-	}
-
-	return danglingNodes
+// RankPersonalized es la versión concurrente de pageRank.RankPersonalized
+func (pr *pageRankConcurrent[K]) RankPersonalized(followingProb, tolerance float64, personalization map[K]float64, personalizedDangling bool, resultFunc func(label K, rank float64)) RankStats {
+	p, stats, _ := pr.rank(context.Background(), pr, RankOptionsOf[K]{
+		FollowingProb:        followingProb,
+		Tolerance:            tolerance,
+		Personalization:      personalization,
+		PersonalizedDangling: personalizedDangling,
+	}, nil)
+	pr.emit(p, resultFunc)
+	return stats
 }
 
-// Calcula el inner product de forma concurrente
-func (pr *pageRankConcurrent) calculateInnerProductConcurrent(p []float64, danglingNodes []int) float64 {
-	if len(danglingNodes) == 0 {
-		return 0.0
-	}
-
-	chunks, numWorkers := pr.calculateWorkChunks(len(danglingNodes))
-
-	// Si solo hay un chunk, ejecutar secuencialmente (más eficiente)
-	if numWorkers == 1 {
-		innerProduct := 0.0
-		for _, danglingNode := range danglingNodes {
-			innerProduct += p[danglingNode]
-		}
-		return innerProduct
-	}
-
-	// Ejecución paralela con arrays locales (mejor cache locality)
-	var wg sync.WaitGroup
-	results := make([]float64, numWorkers)
-
-	for w := 0; w < numWorkers; w++ {
-		wg.Add(1)
-		go func(workerID int, chunk workChunk) {
-			defer wg.Done()
-			localSum := 0.0
-			// Optimización: reducir bounds checking
-			for i := chunk.start; i < chunk.end; i++ {
-				localSum += p[danglingNodes[i]]
-			}
-			results[workerID] = localSum
-		}(w, chunks[w])
-	}
-
-	wg.Wait()
-
-	// Reducción final
-	innerProduct := 0.0
-	for w := 0; w < numWorkers; w++ {
-		innerProduct += results[w]
-	}
-
-	return innerProduct
+// RankContext es la versión concurrente de pageRank.RankContext
+func (pr *pageRankConcurrent[K]) RankContext(ctx context.Context, opts RankOptionsOf[K]) (map[K]float64, RankStats, error) {
+	p, stats, err := pr.rank(ctx, pr, opts, nil)
+	return pr.rankMap(p), stats, err
 }
 
-// Calcula el vector v de forma concurrente - AQUÍ ESTÁ EL MAYOR BENEFICIO
-func (pr *pageRankConcurrent) step(followingProb, tOverSize float64, p []float64, danglingNodes []int) []float64 {
-	innerProduct := pr.calculateInnerProductConcurrent(p, danglingNodes)
-	innerProductOverSize := innerProduct / float64(len(p))
-
-	v := make([]float64, len(p))
-	chunks, numWorkers := pr.calculateWorkChunks(len(pr.inLinks))
-
-	// Si solo hay un chunk, ejecutar secuencialmente
-	if numWorkers == 1 {
-		vsum := 0.0
-		for i := range pr.inLinks {
-			ksum := 0.0
-			for _, index := range pr.inLinks[i] {
-				ksum += p[index] / float64(pr.numberOutLinks[index])
-			}
-			v[i] = followingProb*(ksum+innerProductOverSize) + tOverSize
-			vsum += v[i]
-		}
-
-		// Normalización secuencial
-		inverseOfSum := 1.0 / vsum
-		for i := range v {
-			v[i] *= inverseOfSum
-		}
-		return v
-	}
-
-	// Ejecución paralela: Fase 1 - Calcular v y suma parcial
-	// OPTIMIZACIÓN CRÍTICA: Pre-calcular 1.0/numberOutLinks para evitar divisiones repetidas
-	inverseOutLinks := make([]float64, len(pr.numberOutLinks))
-	for i, outLinks := range pr.numberOutLinks {
-		if outLinks > 0 {
-			inverseOutLinks[i] = 1.0 / float64(outLinks)
-		}
-	}
-
-	vsumParts := make([]float64, numWorkers)
-	var wg sync.WaitGroup
-
-	for w := 0; w < numWorkers; w++ {
-		wg.Add(1)
-		go func(workerID int, chunk workChunk) {
-			defer wg.Done()
-			localVsum := 0.0
-			// OPTIMIZACIÓN: Mejor cache locality
-			for i := chunk.start; i < chunk.end; i++ {
-				ksum := 0.0
-				inLinks := pr.inLinks[i]
-				// Inner loop optimizado - acceso secuencial
-				for j := 0; j < len(inLinks); j++ {
-					index := inLinks[j]
-					ksum += p[index] * inverseOutLinks[index]
-				}
-				v[i] = followingProb*(ksum+innerProductOverSize) + tOverSize
-				localVsum += v[i]
-			}
-			vsumParts[workerID] = localVsum
-		}(w, chunks[w])
-	}
-
-	wg.Wait()
-
-	// Reducción: calcular vsum total
-	vsum := 0.0
-	for w := 0; w < numWorkers; w++ {
-		vsum += vsumParts[w]
-	}
-	inverseOfSum := 1.0 / vsum
-
-	// Fase 2 - Normalización paralela usando los mismos chunks
-	for w := 0; w < numWorkers; w++ {
-		wg.Add(1)
-		go func(workerID int, chunk workChunk) {
-			defer wg.Done()
-			for i := chunk.start; i < chunk.end; i++ {
-				v[i] *= inverseOfSum
-			}
-		}(w, chunks[w])
-	}
-
-	wg.Wait()
-
-	return v
+// RankResults es la versión concurrente de pageRank.RankResults
+func (pr *pageRankConcurrent[K]) RankResults(ctx context.Context, opts RankOptionsOf[K]) (*Ranks[K], RankStats, error) {
+	return pr.rankResults(ctx, pr, opts)
 }
 
-// Calcula el cambio de forma concurrente
-func (pr *pageRankConcurrent) calculateChangeConcurrent(p, new_p []float64) float64 {
-	chunks, numWorkers := pr.calculateWorkChunks(len(p))
-
-	// Si solo hay un chunk, ejecutar secuencialmente
-	if numWorkers == 1 {
-		acc := 0.0
-		for i := range p {
-			acc += math.Abs(p[i] - new_p[i])
-		}
-		return acc
-	}
-
-	// Ejecución paralela
-	results := make([]float64, numWorkers)
-	var wg sync.WaitGroup
-
-	for w := 0; w < numWorkers; w++ {
-		wg.Add(1)
-		go func(workerID int, chunk workChunk) {
-			defer wg.Done()
-			localAcc := 0.0
-			// Mejor localidad de caché
-			for i := chunk.start; i < chunk.end; i++ {
-				diff := p[i] - new_p[i]
-				if diff < 0 {
-					diff = -diff
-				}
-				localAcc += diff
-			}
-			results[workerID] = localAcc
-		}(w, chunks[w])
-	}
-
-	wg.Wait()
-
-	// Reducción final
-	acc := 0.0
-	for w := 0; w < numWorkers; w++ {
-		acc += results[w]
-	}
-
-	return acc
+// RankSeq es la versión concurrente de pageRank.RankSeq
+func (pr *pageRankConcurrent[K]) RankSeq(followingProb, tolerance float64) (iter.Seq2[K, float64], RankStats) {
+	return pr.rankSeq(pr, RankOptionsOf[K]{FollowingProb: followingProb, Tolerance: tolerance})
 }
 
-func (pr *pageRankConcurrent) Rank(followingProb, tolerance float64, resultFunc func(label int, rank float64)) {
-	size := len(pr.keyToIndex)
-	inverseOfSize := 1.0 / float64(size)
-	tOverSize := (1.0 - followingProb) / float64(size)
-	danglingNodes := pr.calculateDanglingNodes()
-
-	p := make([]float64, size)
-	for i := range p {
-		p[i] = inverseOfSize
-	}
-
-	change := 2.0
-
-	for change > tolerance {
-		new_p := pr.step(followingProb, tOverSize, p, danglingNodes)
-		change = pr.calculateChangeConcurrent(p, new_p)
-		p = new_p
-	}
-
-	for i, pForI := range p {
-		resultFunc(pr.indexToKey[i], pForI)
-	}
+// RankInto es la versión concurrente de pageRank.RankInto
+func (pr *pageRankConcurrent[K]) RankInto(ctx context.Context, dst []float64, opts RankOptionsOf[K]) ([]float64, RankStats, error) {
+	return pr.rank(ctx, pr, opts, dst)
 }
 
-func (pr *pageRankConcurrent) Clear() {
-	pr.inLinks = [][]int{}
-	pr.numberOutLinks = []int{}
-	pr.currentAvailableIndex = 0
-	pr.keyToIndex = make(map[int]int)
-	pr.indexToKey = make(map[int]int)
+// ApplyLinks es la versión concurrente de pageRank.ApplyLinks
+func (pr *pageRankConcurrent[K]) ApplyLinks(edges [][2]K) {
+	pr.applyLinks(pr, edges)
 }
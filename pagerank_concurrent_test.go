@@ -1,6 +1,7 @@
 package pagerank
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"testing"
@@ -154,6 +155,160 @@ func TestConcurrentWithDifferentWorkers(t *testing.T) {
 	}
 }
 
+// Verificar que el PageRank personalizado coincide entre ambas versiones
+func TestConcurrentPersonalizedVsSequentialEquality(t *testing.T) {
+	links := [][2]int{
+		{1, 2}, {2, 1}, {3, 0}, {3, 1}, {4, 3},
+		{4, 1}, {4, 5}, {5, 4}, {5, 1}, {6, 1},
+		{6, 4}, {7, 1}, {7, 4}, {8, 1}, {8, 4},
+		{9, 4}, {10, 4},
+	}
+	personalization := map[int]float64{3: 2, 6: 1, 10: 1}
+
+	for _, personalizedDangling := range []bool{false, true} {
+		t.Run(fmt.Sprintf("PersonalizedDangling=%v", personalizedDangling), func(t *testing.T) {
+			prSeq := New()
+			prConc := NewConcurrentWithWorkers(4)
+			for _, link := range links {
+				prSeq.Link(link[0], link[1])
+				prConc.Link(link[0], link[1])
+			}
+
+			seqResults := make(map[int]float64)
+			prSeq.RankPersonalized(0.85, 0.0001, personalization, personalizedDangling, func(label int, rank float64) {
+				seqResults[label] = rank
+			})
+
+			prConc.RankPersonalized(0.85, 0.0001, personalization, personalizedDangling, func(label int, rank float64) {
+				diff := math.Abs(seqResults[label] - rank)
+				if diff > 1e-10 {
+					t.Errorf("Node %d: sequential=%.15f, concurrent=%.15f, diff=%.15e",
+						label, seqResults[label], rank, diff)
+				}
+			})
+		})
+	}
+}
+
+// Verificar que los enlaces con peso dan el mismo resultado en ambas versiones
+func TestConcurrentWeightedVsSequentialEquality(t *testing.T) {
+	links := [][2]int{
+		{1, 2}, {2, 1}, {3, 0}, {3, 1}, {4, 3},
+		{4, 1}, {4, 5}, {5, 4}, {5, 1}, {6, 1},
+		{6, 4}, {7, 1}, {7, 4}, {8, 1}, {8, 4},
+		{9, 4}, {10, 4},
+	}
+
+	prSeq := New()
+	prConc := NewConcurrentWithWorkers(4)
+	for i, link := range links {
+		weight := float64(i%3 + 1)
+		prSeq.LinkWeighted(link[0], link[1], weight)
+		prConc.LinkWeighted(link[0], link[1], weight)
+	}
+
+	seqResults := make(map[int]float64)
+	prSeq.Rank(0.85, 0.0001, func(label int, rank float64) {
+		seqResults[label] = rank
+	})
+
+	prConc.Rank(0.85, 0.0001, func(label int, rank float64) {
+		diff := math.Abs(seqResults[label] - rank)
+		if diff > 1e-10 {
+			t.Errorf("Node %d: sequential=%.15f, concurrent=%.15f, diff=%.15e",
+				label, seqResults[label], rank, diff)
+		}
+	})
+}
+
+// Grafo por encima de parallelizationThreshold para ejercitar la ruta paralela
+func TestConcurrentWeightedLargeGraph(t *testing.T) {
+	n := 2 * parallelizationThreshold
+
+	prSeq := New()
+	prConc := NewConcurrentWithWorkers(4)
+	for i := 0; i < n; i++ {
+		for j := 0; j < 5; j++ {
+			to := (i*7 + j*13) % n
+			weight := float64(j + 1)
+			prSeq.LinkWeighted(i, to, weight)
+			prConc.LinkWeighted(i, to, weight)
+		}
+	}
+
+	seqResults := make(map[int]float64)
+	prSeq.Rank(0.85, 0.0001, func(label int, rank float64) {
+		seqResults[label] = rank
+	})
+
+	prConc.Rank(0.85, 0.0001, func(label int, rank float64) {
+		diff := math.Abs(seqResults[label] - rank)
+		if diff > 1e-10 {
+			t.Errorf("Node %d: sequential=%.15f, concurrent=%.15f, diff=%.15e",
+				label, seqResults[label], rank, diff)
+		}
+	})
+}
+
+// Verificar cada estrategia para nodos colgantes por la ruta paralela
+func TestConcurrentDanglingStrategiesVsSequentialEquality(t *testing.T) {
+	n := 2 * parallelizationThreshold
+
+	prSeq := New()
+	prConc := NewConcurrentWithWorkers(4)
+	buildTestGraph(prSeq, n)
+	buildTestGraph(prConc, n)
+
+	for _, strategy := range []DanglingStrategy{DanglingUniform, DanglingPersonalized, DanglingSelfLoop, DanglingDrop} {
+		t.Run(strategy.String(), func(t *testing.T) {
+			opts := RankOptions{
+				FollowingProb:   0.85,
+				Tolerance:       0.0001,
+				Personalization: map[int]float64{1: 1, 7: 3},
+				Dangling:        strategy,
+			}
+
+			seqResults, _, _ := prSeq.RankContext(context.Background(), opts)
+			concResults, _, _ := prConc.RankContext(context.Background(), opts)
+
+			for label, rank := range concResults {
+				diff := math.Abs(seqResults[label] - rank)
+				if diff > 1e-10 {
+					t.Errorf("Node %d: sequential=%.15f, concurrent=%.15f, diff=%.15e",
+						label, seqResults[label], rank, diff)
+				}
+			}
+		})
+	}
+}
+
+// Verificar que ambas versiones coinciden con claves que no son int
+func TestConcurrentWithStringKeys(t *testing.T) {
+	prSeq := NewGraph[string]()
+	prConc := NewGraphConcurrentWithWorkers[string](4)
+	for i := 0; i < 2*parallelizationThreshold; i++ {
+		for j := 0; j < 3; j++ {
+			from := fmt.Sprintf("node-%d", i)
+			to := fmt.Sprintf("node-%d", (i*7+j*13)%(2*parallelizationThreshold))
+			prSeq.Link(from, to)
+			prConc.Link(from, to)
+		}
+	}
+
+	seqResults := make(map[string]float64)
+	prSeq.Rank(0.85, 0.0001, func(label string, rank float64) {
+		seqResults[label] = rank
+	})
+
+	prConc.Rank(0.85, 0.0001, func(label string, rank float64) {
+		diff := math.Abs(seqResults[label] - rank)
+		if diff > 1e-10 {
+			t.Errorf("Node %s: sequential=%.15f, concurrent=%.15f, diff=%.15e",
+				label, seqResults[label], rank, diff)
+		}
+	})
+}
+
 // Benchmark comparando versiones
 func BenchmarkSequentialSmall(b *testing.B) {
 	links := [][2]int{
@@ -1,6 +1,7 @@
 package pagerank
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"math/rand"
@@ -80,6 +81,21 @@ func TestShouldBePossibleToClearTheGraph(t *testing.T) {
 	assertRank(t, pageRank, map[int]float64{0: 35.1, 1: 64.9})
 }
 
+func TestShouldIgnoreNonFiniteWeights(t *testing.T) {
+	pageRank := New()
+	pageRank.Link(0, 1)
+	pageRank.LinkWeighted(1, 0, math.NaN())
+	pageRank.LinkWeighted(0, 2, math.Inf(1))
+	pageRank.LinkWeighted(3, 0, math.Inf(-1))
+
+	//without MaxIterations a NaN rank would never converge
+	_, stats, err := pageRank.RankContext(context.Background(), RankOptions{FollowingProb: 0.85, Tolerance: 0.0001})
+
+	assertEqual(t, err, nil)
+	assert(t, !math.IsNaN(stats.Residual))
+	assertRank(t, pageRank, map[int]float64{0: 35.1, 1: 64.9})
+}
+
 func TestShouldNotFailWhenCalculatingTheRankOfAnEmptyGraph(t *testing.T) {
 	pageRank := New()
 	pageRank.Rank(0.85, 0.0001, func(label int, rank float64) {
@@ -207,6 +223,245 @@ func TestShouldCorrectlyReproduceTheWikipediaExample(t *testing.T) {
 	assertRank(t, pageRank, expectedRank)
 }
 
+func TestShouldReproduceTheWikipediaExampleWithWeightedLinks(t *testing.T) {
+	//scaling every weight by the same factor must not change the ranks
+	pageRank := New()
+	pageRank.LinkWeighted(1, 2, 2.5)
+	pageRank.LinkWeighted(2, 1, 2.5)
+	pageRank.LinkWeighted(3, 0, 2.5)
+	pageRank.LinkWeighted(3, 1, 2.5)
+	pageRank.LinkWeighted(4, 3, 2.5)
+	pageRank.LinkWeighted(4, 1, 2.5)
+	pageRank.LinkWeighted(4, 5, 2.5)
+	pageRank.LinkWeighted(5, 4, 2.5)
+	pageRank.LinkWeighted(5, 1, 2.5)
+	pageRank.LinkWeighted(6, 1, 2.5)
+	pageRank.LinkWeighted(6, 4, 2.5)
+	pageRank.LinkWeighted(7, 1, 2.5)
+	pageRank.LinkWeighted(7, 4, 2.5)
+	pageRank.LinkWeighted(8, 1, 2.5)
+	pageRank.LinkWeighted(8, 4, 2.5)
+	pageRank.LinkWeighted(9, 4, 2.5)
+	pageRank.LinkWeighted(10, 4, 2.5)
+
+	expectedRank := map[int]float64{
+		0:  3.3,  //a
+		1:  38.4, //b
+		2:  34.3, //c
+		3:  3.9,  //d
+		4:  8.1,  //e
+		5:  3.9,  //f
+		6:  1.6,  //g
+		7:  1.6,  //h
+		8:  1.6,  //i
+		9:  1.6,  //j
+		10: 1.6,  //k
+	}
+
+	assertRank(t, pageRank, expectedRank)
+}
+
+func TestWeightedLinkShouldBeEquivalentToRepeatedLinks(t *testing.T) {
+	weighted := New()
+	weighted.Link(0, 2)
+	weighted.LinkWeighted(0, 1, 3)
+	weighted.Link(1, 2)
+	weighted.LinkWeighted(2, 0, 0.5)
+
+	repeated := New()
+	repeated.Link(0, 2)
+	repeated.Link(0, 1)
+	repeated.Link(0, 1)
+	repeated.Link(0, 1)
+	repeated.Link(1, 2)
+	repeated.Link(2, 0)
+
+	expected := make(map[int]float64)
+	repeated.Rank(0.85, 0.0001, func(label int, rank float64) {
+		expected[label] = rank
+	})
+
+	weighted.Rank(0.85, 0.0001, func(label int, rank float64) {
+		if math.Abs(rank-expected[label]) > 1e-10 {
+			t.Error("Rank for", label, "should be", expected[label], "but was", rank)
+		}
+	})
+}
+
+func TestShouldIgnoreNonPositiveWeights(t *testing.T) {
+	pageRank := New()
+	pageRank.Link(0, 1)
+	pageRank.LinkWeighted(0, 2, 0)
+	pageRank.LinkWeighted(3, 0, -1)
+
+	assertRank(t, pageRank, map[int]float64{0: 35.1, 1: 64.9})
+}
+
+func TestShouldReproduceTheWikipediaExampleWithStringKeys(t *testing.T) {
+	var pageRank Graph[string] = NewGraph[string]()
+	pageRank.Link("b", "c")
+	pageRank.Link("c", "b")
+	pageRank.Link("d", "a")
+	pageRank.Link("d", "b")
+	pageRank.Link("e", "d")
+	pageRank.Link("e", "b")
+	pageRank.Link("e", "f")
+	pageRank.Link("f", "e")
+	pageRank.Link("f", "b")
+	pageRank.Link("g", "b")
+	pageRank.Link("g", "e")
+	pageRank.Link("h", "b")
+	pageRank.Link("h", "e")
+	pageRank.Link("i", "b")
+	pageRank.Link("i", "e")
+	pageRank.Link("j", "e")
+	pageRank.Link("k", "e")
+
+	expectedRank := map[string]float64{
+		"a": 3.3,
+		"b": 38.4,
+		"c": 34.3,
+		"d": 3.9,
+		"e": 8.1,
+		"f": 3.9,
+		"g": 1.6,
+		"h": 1.6,
+		"i": 1.6,
+		"j": 1.6,
+		"k": 1.6,
+	}
+
+	pageRank.Rank(0.85, 0.0001, func(label string, rank float64) {
+		if math.Abs(toPercentage(rank)-expectedRank[label]) > 0.0001 {
+			t.Error("Rank for", label, "should be", expectedRank[label], "but was", toPercentage(rank))
+		}
+	})
+}
+
+func TestShouldBeUniformForACircularGraphWithUUIDKeys(t *testing.T) {
+	uuid := func(b byte) [16]byte {
+		return [16]byte{0: 0xca, 1: 0xfe, 15: b}
+	}
+
+	pageRank := NewGraph[[16]byte]()
+	for i := byte(0); i < 5; i++ {
+		pageRank.Link(uuid(i), uuid((i+1)%5))
+	}
+
+	ranks := 0
+	pageRank.Rank(0.85, 0.0001, func(label [16]byte, rank float64) {
+		ranks++
+		assertEqual(t, label[0], byte(0xca))
+		assertEqual(t, toPercentage(rank), 20.0)
+	})
+	assertEqual(t, ranks, 5)
+}
+
+func TestPersonalizedRankShouldFollowTheTeleportVector(t *testing.T) {
+	pageRank := New()
+	pageRank.Link(0, 1)
+	pageRank.Link(1, 0)
+
+	// x0 = 0.85*x1 + 0.15, x1 = 0.85*x0  =>  x0 = 1/1.85
+	expectedRank := map[int]float64{
+		0: 54.1,
+		1: 45.9,
+	}
+
+	pageRank.RankPersonalized(0.85, 0.0001, map[int]float64{0: 1}, false, func(label int, rank float64) {
+		if math.Abs(toPercentage(rank)-expectedRank[label]) > 0.0001 {
+			t.Error("Rank for", label, "should be", expectedRank[label], "but was", toPercentage(rank))
+		}
+	})
+}
+
+func TestPersonalizedRankShouldSendDanglingMassToThePersonalizationVector(t *testing.T) {
+	pageRank := New()
+	//node 1 is a dangling node, its mass goes back to node 0
+	pageRank.Link(0, 1)
+
+	expectedRank := map[int]float64{
+		0: 54.1,
+		1: 45.9,
+	}
+
+	pageRank.RankPersonalized(0.85, 0.0001, map[int]float64{0: 1}, true, func(label int, rank float64) {
+		if math.Abs(toPercentage(rank)-expectedRank[label]) > 0.0001 {
+			t.Error("Rank for", label, "should be", expectedRank[label], "but was", toPercentage(rank))
+		}
+	})
+}
+
+func TestPersonalizedRankWithoutValidWeightsShouldBeUniform(t *testing.T) {
+	pageRank := New()
+	pageRank.Link(0, 2)
+	pageRank.Link(1, 2)
+
+	expectedRank := map[int]float64{
+		0: 21.3,
+		1: 21.3,
+		2: 57.4,
+	}
+
+	pageRank.RankPersonalized(0.85, 0.0001, map[int]float64{7: 1, 0: -1}, true, func(label int, rank float64) {
+		if math.Abs(toPercentage(rank)-expectedRank[label]) > 0.0001 {
+			t.Error("Rank for", label, "should be", expectedRank[label], "but was", toPercentage(rank))
+		}
+	})
+}
+
+func TestPersonalizedRankShouldIgnoreNonFiniteWeights(t *testing.T) {
+	pageRank := New()
+	pageRank.Link(0, 1)
+	pageRank.Link(1, 0)
+
+	expectedRank := map[int]float64{
+		0: 54.1,
+		1: 45.9,
+	}
+
+	personalization := map[int]float64{0: 1, 1: math.Inf(1)}
+	pageRank.RankPersonalized(0.85, 0.0001, personalization, false, func(label int, rank float64) {
+		if math.Abs(toPercentage(rank)-expectedRank[label]) > 0.0001 {
+			t.Error("Rank for", label, "should be", expectedRank[label], "but was", toPercentage(rank))
+		}
+	})
+}
+
+func TestDanglingStrategiesShouldMatchTheirReferenceValues(t *testing.T) {
+	//node 1 is a dangling node
+	expectedRanks := map[DanglingStrategy]map[int]float64{
+		// x0 = 0.075 + 0.425*x1
+		DanglingUniform: {0: 35.1, 1: 64.9},
+		// x0 = 0.15 + 0.85*x1, same as RankPersonalized with personalizedDangling
+		DanglingPersonalized: {0: 54.1, 1: 45.9},
+		// x1 keeps its own mass: x0 = 0.075
+		DanglingSelfLoop: {0: 7.5, 1: 92.5},
+		// s*x0 = 0.075, s*x1 = 0.85*x0 + 0.075 with x0 + x1 = 1
+		DanglingDrop: {0: 22.2, 1: 77.8},
+	}
+
+	for _, pageRank := range []Interface{New(), NewConcurrent()} {
+		pageRank.Link(0, 1)
+
+		for strategy, expectedRank := range expectedRanks {
+			opts := RankOptions{FollowingProb: 0.85, Tolerance: 0.0001, Dangling: strategy}
+			if strategy == DanglingPersonalized {
+				opts.Personalization = map[int]float64{0: 1}
+			}
+
+			ranks, _, err := pageRank.RankContext(context.Background(), opts)
+
+			assertEqual(t, err, nil)
+			for label, rank := range ranks {
+				if math.Abs(toPercentage(rank)-expectedRank[label]) > 0.0001 {
+					t.Error(strategy, "rank for", label, "should be", expectedRank[label], "but was", toPercentage(rank))
+				}
+			}
+		}
+	}
+}
+
 func BenchmarkOneHundredThousand(b *testing.B) {
 	n := 100_000
 
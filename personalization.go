@@ -0,0 +1,51 @@
+package pagerank
+
+import "math"
+
+// teleport describe la distribución con la que el navegante aleatorio salta a
+// un nodo cualquiera. Un vector nil representa la distribución uniforme.
+type teleport struct {
+	vector   []float64
+	uniform  float64
+	dangling DanglingStrategy
+}
+
+// newTeleport construye el vector de teletransporte a partir de los pesos por
+// clave. Las claves que no están en el grafo y los pesos no positivos o no
+// finitos se ignoran; si no queda ningún peso se usa la distribución uniforme (y la masa
+// colgante que debía seguir a la personalización también es uniforme).
+func newTeleport[K comparable](personalization map[K]float64, keyToIndex map[K]int, size int, dangling DanglingStrategy) teleport {
+	t := teleport{uniform: 1.0 / float64(size), dangling: dangling}
+	if dangling == DanglingPersonalized {
+		t.dangling = DanglingUniform
+	}
+
+	total := 0.0
+	for key, weight := range personalization {
+		if _, ok := keyToIndex[key]; ok && weight > 0 && !math.IsInf(weight, 0) {
+			total += weight
+		}
+	}
+
+	if total == 0 {
+		return t
+	}
+
+	t.vector = make([]float64, size)
+	for key, weight := range personalization {
+		if index, ok := keyToIndex[key]; ok && weight > 0 && !math.IsInf(weight, 0) {
+			t.vector[index] = weight / total
+		}
+	}
+	t.dangling = dangling
+
+	return t
+}
+
+// at devuelve la probabilidad de saltar al nodo i
+func (t teleport) at(i int) float64 {
+	if t.vector == nil {
+		return t.uniform
+	}
+	return t.vector[i]
+}
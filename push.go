@@ -0,0 +1,99 @@
+package pagerank
+
+// Kernel decide cómo se calcula el rank que recibe cada nodo en la iteración
+// de potencias
+type Kernel int
+
+const (
+	// PullKernel recorre los enlaces entrantes de cada nodo y suma el rank
+	// de sus orígenes (el cálculo original)
+	PullKernel Kernel = iota
+	// PushKernel recorre los enlaces salientes de cada nodo y reparte su
+	// rank entre los destinos. Necesita además la adyacencia saliente, que
+	// se construye la primera vez y se guarda con la forma congelada. La
+	// versión concurrente acumula en un búfer por worker y después los suma.
+	PushKernel
+)
+
+func (k Kernel) String() string {
+	if k == PushKernel {
+		return "push"
+	}
+	return "pull"
+}
+
+// buildOutLinks construye la adyacencia saliente a partir de la entrante
+// (la transpuesta de la forma CSR). Los destinos de cada origen quedan en
+// orden de índice.
+func (c *csr) buildOutLinks() {
+	if c.outOffsets != nil {
+		return
+	}
+
+	size := c.size()
+	c.outOffsets = make([]int, size+1)
+	for _, from := range c.inLinks {
+		c.outOffsets[from+1]++
+	}
+	for i := 0; i < size; i++ {
+		c.outOffsets[i+1] += c.outOffsets[i]
+	}
+
+	c.outLinks = make([]uint32, len(c.inLinks))
+	if c.weights != nil {
+		c.outWeights = make([]float64, len(c.inLinks))
+	}
+
+	next := append([]int(nil), c.outOffsets[:size]...)
+	for i := 0; i < size; i++ {
+		for j := c.offsets[i]; j < c.offsets[i+1]; j++ {
+			from := c.inLinks[j]
+			c.outLinks[next[from]] = uint32(i)
+			if c.weights != nil {
+				c.outWeights[next[from]] = c.weights[j]
+			}
+			next[from]++
+		}
+	}
+}
+
+// scatter suma en acc el rank que los orígenes [start, end) mandan a cada
+// uno de sus destinos
+func (c *csr) scatter(p, acc []float64, start, end int) {
+	for from := start; from < end; from++ {
+		share := p[from] * c.inverseOutLinks[from]
+		first, last := c.outOffsets[from], c.outOffsets[from+1]
+
+		if c.outWeights == nil {
+			for _, to := range c.outLinks[first:last] {
+				acc[to] += share
+			}
+			continue
+		}
+
+		weights := c.outWeights[first:last]
+		for j, to := range c.outLinks[first:last] {
+			acc[to] += share * weights[j]
+		}
+	}
+}
+
+// sequentialPushStep es sequentialStep con el kernel push: v acumula primero
+// el rank que llega por los enlaces y después se completa con el
+// teletransporte. Con compensated se compensan la suma del vector y la masa
+// colgante; el rank de cada nodo llega en el orden de sus orígenes.
+func sequentialPushStep(followingProb float64, t teleport, c *csr, p, v []float64, options engineOptions) Change {
+	innerProduct := danglingMass(p, c.danglingNodes, options.compensated)
+
+	clear(v)
+	c.scatter(p, v, 0, c.size())
+
+	vsum := accumulator{compensated: options.compensated}
+	for i := range v {
+		v[i] = followingProb*(v[i]+t.danglingTerm(i, innerProduct, c, p)) + (1.0-followingProb)*t.at(i)
+		vsum.add(v[i])
+	}
+
+	acc := normalizeWithChange(p, v, 1.0/vsum.value())
+	return acc.change(p, v)
+}
@@ -0,0 +1,145 @@
+package pagerank
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestBuildOutLinksShouldTransposeTheInLinks(t *testing.T) {
+	pageRank := New()
+	pageRank.LinkWeighted(0, 1, 2)
+	pageRank.LinkWeighted(0, 2, 3)
+	pageRank.Link(2, 0)
+	pageRank.Link(1, 2)
+	pageRank.Link(3, 3)
+	pageRank.Link(4, 1)
+	c := pageRank.frozenForm()
+
+	c.buildOutLinks()
+
+	assertEqual(t, c.outOffsets[c.size()], len(c.inLinks))
+	for from := 0; from < c.size(); from++ {
+		first, last := c.outOffsets[from], c.outOffsets[from+1]
+		for j, to := range c.outLinks[first:last] {
+			//the same link must exist among the in-links of the target with the same weight
+			found := false
+			for k := c.offsets[to]; k < c.offsets[to+1]; k++ {
+				if int(c.inLinks[k]) == from && c.weights[k] == c.outWeights[first+j] {
+					found = true
+				}
+			}
+			assert(t, found)
+			if j > 0 {
+				assert(t, c.outLinks[first+j-1] < to)
+			}
+		}
+	}
+
+	index := func(key int) int { return pageRank.keyToIndex[key] }
+	assertEqual(t, c.outOffsets[index(0)+1]-c.outOffsets[index(0)], 2)
+	assertEqual(t, c.outOffsets[index(3)+1]-c.outOffsets[index(3)], 1)
+}
+
+func TestPushKernelShouldGiveTheSameRanks(t *testing.T) {
+	const n = 4 * parallelizationThreshold
+
+	for _, dangling := range []DanglingStrategy{DanglingUniform, DanglingSelfLoop, DanglingDrop} {
+		for _, pageRank := range []Interface{New(), NewConcurrentWithWorkers(4)} {
+			hubGraph(pageRank, n)
+			buildTestGraph(pageRank, n+n/2)
+			pageRank.LinkWeighted(3, 7, 5)
+
+			opts := RankOptions{FollowingProb: 0.85, Tolerance: 1e-12, Dangling: dangling}
+			expected, _, _ := pageRank.RankInto(context.Background(), nil, opts)
+
+			for _, scheduling := range []Scheduling{EdgeBalanced, NodeBalanced, DynamicScheduling} {
+				opts.Kernel = PushKernel
+				opts.Scheduling = scheduling
+				ranks, _, err := pageRank.RankInto(context.Background(), nil, opts)
+
+				assertEqual(t, err, nil)
+				for i := range expected {
+					if math.Abs(ranks[i]-expected[i]) > 1e-12 {
+						t.Fatal(dangling, scheduling, "rank for", i, "should be", expected[i], "but was", ranks[i])
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestPushKernelShouldReportTheOutLinksAsLoad(t *testing.T) {
+	const n = 4 * parallelizationThreshold
+
+	for _, scheduling := range []Scheduling{EdgeBalanced, NodeBalanced, DynamicScheduling} {
+		pageRank := NewConcurrentWithWorkers(4)
+		hubGraph(pageRank, n)
+
+		_, stats, _ := pageRank.RankContext(context.Background(), RankOptions{
+			FollowingProb: 0.85,
+			Tolerance:     1e-8,
+			Scheduling:    scheduling,
+			Kernel:        PushKernel,
+		})
+
+		nodes, edges := 0, 0
+		for _, load := range stats.WorkerLoads {
+			nodes += load.Nodes
+			edges += load.Edges
+		}
+		assertEqual(t, nodes, stats.Iterations*n)
+		assertEqual(t, edges, stats.Iterations*3*n)
+	}
+}
+
+func TestPushKernelShouldLeaveTheBuffersEmpty(t *testing.T) {
+	const n = 2 * parallelizationThreshold
+
+	pageRank := NewConcurrentWithWorkers(3)
+	buildTestGraph(pageRank, n)
+	c := pageRank.frozenForm()
+	c.buildOutLinks()
+	tp := newTeleport[int](nil, nil, c.size(), DanglingUniform)
+	p := make([]float64, n)
+	for i := range p {
+		p[i] = 1.0 / n
+	}
+
+	stop := pageRank.start(c, engineOptions{kernel: PushKernel})
+	pageRank.step(0.85, tp, c, p, make([]float64, n))
+	for _, part := range pageRank.pool.parts {
+		for _, x := range part.buffer {
+			assertEqual(t, x, 0.0)
+		}
+	}
+	stop(&RankStats{})
+}
+
+func BenchmarkKernels(b *testing.B) {
+	const n = 200000
+	edges := testEdges(n)
+
+	engines := []struct {
+		name     string
+		pageRank Interface
+	}{
+		{"sequential", New()},
+		{"concurrent", NewConcurrent()},
+	}
+	for _, engine := range engines {
+		name, pageRank := engine.name, engine.pageRank
+		pageRank.AddEdges(edges)
+		pageRank.Freeze()
+		dst := make([]float64, n)
+
+		for _, kernel := range []Kernel{PullKernel, PushKernel} {
+			b.Run(name+"-"+kernel.String(), func(b *testing.B) {
+				opts := RankOptions{FollowingProb: 0.85, Tolerance: 1e-8, Kernel: kernel}
+				for i := 0; i < b.N; i++ {
+					dst, _, _ = pageRank.RankInto(context.Background(), dst, opts)
+				}
+			})
+		}
+	}
+}
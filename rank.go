@@ -0,0 +1,326 @@
+package pagerank
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrNotConverged indica que se alcanzó MaxIterations antes de que el cambio
+// entre iteraciones bajara de la tolerancia.
+var ErrNotConverged = errors.New("pagerank: did not converge within MaxIterations")
+
+// ErrNaN indica que el cambio entre iteraciones dio NaN: algún valor de
+// entrada no era finito y el vector ya no puede converger.
+var ErrNaN = errors.New("pagerank: residual is NaN")
+
+// RankOptionsOf configura una llamada a RankContext sobre un grafo con claves
+// de tipo K
+type RankOptionsOf[K comparable] struct {
+	FollowingProb float64 // Probabilidad de seguir un enlace (típicamente 0.85)
+	Tolerance     float64 // Cambio L1 por debajo del cual se considera convergido
+	MaxIterations int     // Límite de iteraciones, 0 = sin límite
+
+	// Convergence reemplaza el criterio de parada; nil equivale a
+	// L1Norm(Tolerance)
+	Convergence ConvergenceCriterion
+
+	// Personalization asigna un peso de teletransporte por clave de nodo;
+	// nil equivale al salto uniforme de Rank
+	Personalization      map[K]float64
+	PersonalizedDangling bool // Equivale a Dangling: DanglingPersonalized
+
+	Dangling DanglingStrategy // Destino de la masa colgante, DanglingUniform por defecto
+
+	// Initial siembra la iteración con un vector previo por clave. Si es nil
+	// y WarmStart es true se parte del último resultado de este grafo. Los
+	// nodos sin valor previo (o con uno no positivo o no finito) arrancan
+	// con 1/N y el vector se renormaliza.
+	Initial   map[K]float64
+	WarmStart bool
+
+	Solver Solver  // Método de iteración, PowerIteration por defecto
+	Omega  float64 // Factor de relajación de SOR, 0 equivale a 1
+
+	Scheduling Scheduling // Reparto entre workers del motor concurrente, EdgeBalanced por defecto
+
+	// Kernel elige entre sumar por enlaces entrantes (pull, por defecto) o
+	// repartir por enlaces salientes (push). Sólo afecta a PowerIteration
+	// fuera de los modos determinista y adaptativo; en el resto de los casos
+	// se usa pull.
+	Kernel Kernel
+
+	// Adaptive congela cada nodo cuyo cambio relativo entre dos iteraciones
+	// baja de AdaptiveTolerance (0 = Tolerance) y deja de recalcularlo, como
+	// el PageRank adaptativo de Kamvar, Haveliwala y Golub. Cada
+	// adaptiveRecheckPeriod iteraciones, y antes de dar por convergido, se
+	// hace un paso completo. Al converger, RankStats.AdaptiveBound acota la
+	// distancia L1 entre el resultado y el PageRank exacto.
+	Adaptive          bool
+	AdaptiveTolerance float64
+
+	// Deterministic hace que el resultado sea idéntico bit a bit con
+	// cualquier cantidad de workers y en ambos motores: las sumas se hacen
+	// por bloques fijos de nodos combinados por pares. Con GaussSeidel y SOR
+	// ambos motores barren por esos bloques, como la versión concurrente.
+	Deterministic bool
+
+	// CompensatedSummation usa la suma compensada de Neumaier para el rank
+	// que entra a cada nodo, la suma del vector y la masa colgante. En
+	// grafos con millones de nodos evita que el error de redondeo crezca con
+	// la cantidad de términos, a cambio de unas pocas operaciones por enlace.
+	CompensatedSummation bool
+
+	// Extrapolation acelera la iteración sustituyendo cada
+	// ExtrapolationPeriod iteraciones (0 = 10) el vector por su límite estimado
+	Extrapolation       Extrapolation
+	ExtrapolationPeriod int
+}
+
+// Solver selecciona cómo se recorre el vector en cada iteración
+type Solver int
+
+const (
+	// PowerIteration calcula cada paso sólo con el vector anterior (Jacobi)
+	PowerIteration Solver = iota
+	// GaussSeidel usa dentro del mismo barrido los valores ya actualizados.
+	// La versión concurrente lo aplica por bloques: cada worker usa sus
+	// propios valores nuevos y los del resto de la iteración anterior.
+	GaussSeidel
+	// SOR es Gauss-Seidel con sobre-relajación:
+	// x_i = (1-Omega)*x_i + Omega*gs_i
+	SOR
+)
+
+func (s Solver) String() string {
+	switch s {
+	case GaussSeidel:
+		return "gauss-seidel"
+	case SOR:
+		return "sor"
+	default:
+		return "power"
+	}
+}
+
+// relaxation devuelve el Omega efectivo del barrido Gauss-Seidel
+func (opts RankOptionsOf[K]) relaxation() float64 {
+	if opts.Solver == SOR && opts.Omega != 0 {
+		return opts.Omega
+	}
+	return 1.0
+}
+
+// RankOptions son las opciones del grafo con claves int
+type RankOptions = RankOptionsOf[int]
+
+// RankStats describe cómo transcurrió un cálculo de PageRank
+type RankStats struct {
+	Iterations     int             // Número de pasos ejecutados
+	Residual       float64         // Cambio de la última iteración según el criterio (0 si no hubo ninguna)
+	Residuals      []float64       // Cambio después de cada iteración
+	IterationTimes []time.Duration // Tiempo de pared de cada iteración
+	DanglingNodes  int             // Nodos sin enlaces salientes
+	Extrapolations int             // Veces que se sustituyó el vector por su extrapolación
+	WorkerLoads    []WorkerLoad    // Carga de cada worker, nil si el cálculo no usó workers
+	SkippedUpdates int             // Cálculos de nodos que Adaptive evitó, sumando todas las iteraciones
+	AdaptiveBound  float64         // Cota de la distancia L1 al PageRank exacto, sólo con Adaptive
+}
+
+// engine es el núcleo numérico que cada implementación aporta al bucle de
+// iteración compartido
+type engine interface {
+	// start prepara el motor para iterar sobre c y devuelve la función que
+	// libera lo que haya reservado y completa las estadísticas del motor
+	start(c *csr, options engineOptions) (stop func(stats *RankStats))
+	// step y gaussSeidelStep escriben en v el siguiente iterado, ya
+	// normalizado, y devuelven su cambio respecto de p
+	step(followingProb float64, t teleport, c *csr, p, v []float64) Change
+	gaussSeidelStep(followingProb, omega float64, t teleport, c *csr, p, v []float64) Change
+}
+
+// engineOptions son las opciones de RankOptions que interpreta cada motor
+type engineOptions struct {
+	scheduling    Scheduling
+	deterministic bool
+	compensated   bool
+	kernel        Kernel
+	frozen        []bool // nodos que Adaptive dejó de recalcular, nil sin Adaptive
+}
+
+// engineOptions extrae de opts las opciones de los motores
+func (opts RankOptionsOf[K]) engineOptions() engineOptions {
+	options := engineOptions{
+		scheduling:    opts.Scheduling,
+		deterministic: opts.Deterministic,
+		compensated:   opts.CompensatedSummation,
+	}
+	if opts.Kernel == PushKernel && !opts.Deterministic && !opts.Adaptive && opts.Solver == PowerIteration {
+		options.kernel = PushKernel
+	}
+	return options
+}
+
+// convergence devuelve el criterio de parada efectivo
+func (opts RankOptionsOf[K]) convergence() ConvergenceCriterion {
+	if opts.Convergence != nil {
+		return opts.Convergence
+	}
+	return L1Norm(opts.Tolerance)
+}
+
+// rank ejecuta la iteración de potencias con el núcleo e. Siempre devuelve el
+// último vector calculado y sus estadísticas, incluso cuando se interrumpe por
+// ctx o por MaxIterations. El resultado se escribe en dst, que se agranda sólo
+// si no tiene capacidad suficiente; el segundo búfer de la iteración se guarda
+// en el grafo para la próxima llamada, así que el bucle no reserva memoria.
+// Las llamadas simultáneas sobre el mismo grafo se ejecutan de a una.
+func (g *graph[K]) rank(ctx context.Context, e engine, opts RankOptionsOf[K], dst []float64) (_ []float64, stats RankStats, err error) {
+	g.ranking.Lock()
+	defer g.ranking.Unlock()
+
+	c := g.frozenForm()
+	t := newTeleport(opts.Personalization, g.keyToIndex, c.size(), opts.danglingStrategy())
+	stats = RankStats{DanglingNodes: len(c.danglingNodes)}
+
+	dst = resize(dst, c.size())
+	p := g.initialVector(opts, dst)
+	v := resize(g.scratch, len(p))
+	inDst := true // p es dst y v el búfer del grafo, o al revés
+
+	extrapolator := newExtrapolator(opts.Extrapolation, opts.ExtrapolationPeriod)
+	criterion := opts.convergence()
+	criterion.Reset()
+	converged := false
+	g.lastOptions = opts
+
+	options := opts.engineOptions()
+	if options.kernel == PushKernel {
+		c.buildOutLinks()
+	}
+	var adaptive *adaptiveState
+	if opts.Adaptive {
+		adaptive = newAdaptiveState(c.size(), opts.adaptiveTolerance())
+		options.frozen = adaptive.frozen
+	}
+	stop := e.start(c, options)
+	defer stop(&stats)
+
+	defer func() {
+		if !inDst {
+			copy(dst, p)
+			p, v = dst, p
+		}
+		g.scratch = v
+		g.lastRanks = append(g.lastRanks[:0], p...)
+		g.lastRanksStale = err != nil
+	}()
+
+	for iterations := 0; !converged; iterations++ {
+		if err = ctx.Err(); err != nil {
+			return dst, stats, err
+		}
+		if opts.MaxIterations > 0 && iterations == opts.MaxIterations {
+			err = ErrNotConverged
+			return dst, stats, err
+		}
+
+		start := time.Now()
+		var step Change
+		switch opts.Solver {
+		case GaussSeidel, SOR:
+			step = e.gaussSeidelStep(opts.FollowingProb, opts.relaxation(), t, c, p, v)
+		default:
+			step = e.step(opts.FollowingProb, t, c, p, v)
+		}
+		var change float64
+		change, converged = criterion.Converged(step)
+		if adaptive != nil {
+			stats.SkippedUpdates += adaptive.count
+			converged = adaptive.observe(iterations+1, p, v, converged)
+		}
+		p, v = v, p
+		inDst = !inDst
+
+		if adaptive != nil && converged {
+			stats.AdaptiveBound = adaptiveBound(e, opts.Solver, opts.FollowingProb, t, c, step, p, v)
+		}
+
+		// Sólo se extrapola si la iteración continúa: el resultado devuelto
+		// siempre es un iterado que cumplió la tolerancia
+		if !converged && extrapolator.observe(iterations+1, p) {
+			stats.Extrapolations++
+		}
+
+		stats.Iterations++
+		stats.Residual = change
+		stats.Residuals = append(stats.Residuals, change)
+		stats.IterationTimes = append(stats.IterationTimes, time.Since(start))
+
+		// NaN nunca cumple la tolerancia: sin este corte el bucle no termina
+		if math.IsNaN(change) || math.IsNaN(step.L1) {
+			err = ErrNaN
+			return dst, stats, err
+		}
+	}
+
+	return dst, stats, nil
+}
+
+// resize devuelve buffer con longitud size, reutilizando su capacidad
+func resize(buffer []float64, size int) []float64 {
+	if cap(buffer) < size {
+		return make([]float64, size)
+	}
+	return buffer[:size]
+}
+
+// initialVector escribe en p el vector con el que arranca la iteración:
+// uniforme por defecto, o a partir de opts.Initial / del último resultado si
+// se pidió.
+func (g *graph[K]) initialVector(opts RankOptionsOf[K], p []float64) []float64 {
+	inverseOfSize := 1.0 / float64(len(p))
+
+	for i := range p {
+		p[i] = inverseOfSize
+	}
+
+	switch {
+	case opts.Initial != nil:
+		for key, rank := range opts.Initial {
+			if index, ok := g.keyToIndex[key]; ok && rank > 0 && !math.IsInf(rank, 0) {
+				p[index] = rank
+			}
+		}
+	case opts.WarmStart && g.lastRanks != nil:
+		// RemoveNode y Reorder mantienen lastRanks alineado con los índices
+		// actuales; los nodos más allá de su largo son nuevos
+		for i, rank := range g.lastRanks {
+			if rank > 0 {
+				p[i] = rank
+			}
+		}
+	default:
+		return p
+	}
+
+	normalize(p)
+	return p
+}
+
+// emit entrega cada rank con su clave original
+func (g *graph[K]) emit(p []float64, resultFunc func(label K, rank float64)) {
+	for i, pForI := range p {
+		resultFunc(g.indexToKey[i], pForI)
+	}
+}
+
+// rankMap traduce el vector de ranks a un mapa clave -> rank
+func (g *graph[K]) rankMap(p []float64) map[K]float64 {
+	ranks := make(map[K]float64, len(p))
+	g.emit(p, func(label K, rank float64) {
+		ranks[label] = rank
+	})
+	return ranks
+}
@@ -0,0 +1,381 @@
+package pagerank
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+)
+
+func wikipediaExample(pageRank Interface) {
+	//http://en.wikipedia.org/wiki/File:PageRanks-Example.svg
+	for _, link := range [][2]int{
+		{1, 2}, {2, 1}, {3, 0}, {3, 1}, {4, 3},
+		{4, 1}, {4, 5}, {5, 4}, {5, 1}, {6, 1},
+		{6, 4}, {7, 1}, {7, 4}, {8, 1}, {8, 4},
+		{9, 4}, {10, 4},
+	} {
+		pageRank.Link(link[0], link[1])
+	}
+}
+
+func assertSumsToOne(t *testing.T, ranks map[int]float64) {
+	sum := 0.0
+	for _, rank := range ranks {
+		sum += rank
+	}
+
+	if math.Abs(sum-1.0) > 1e-9 {
+		t.Error("Sum of ranks should be 1.0 but was", sum)
+	}
+}
+
+func TestRankContextShouldMatchRank(t *testing.T) {
+	for _, pageRank := range []Interface{New(), NewConcurrentWithWorkers(4)} {
+		wikipediaExample(pageRank)
+
+		expected := make(map[int]float64)
+		pageRank.Rank(0.85, 0.0001, func(label int, rank float64) {
+			expected[label] = rank
+		})
+
+		ranks, _, err := pageRank.RankContext(context.Background(), RankOptions{
+			FollowingProb: 0.85,
+			Tolerance:     0.0001,
+			MaxIterations: 1000,
+		})
+
+		assertEqual(t, err, nil)
+		assertEqual(t, len(ranks), len(expected))
+		for label, rank := range ranks {
+			assertEqual(t, rank, expected[label])
+		}
+	}
+}
+
+func TestRankContextShouldStopAtMaxIterations(t *testing.T) {
+	for _, pageRank := range []Interface{New(), NewConcurrentWithWorkers(4)} {
+		wikipediaExample(pageRank)
+
+		ranks, _, err := pageRank.RankContext(context.Background(), RankOptions{
+			FollowingProb: 0.85,
+			Tolerance:     0,
+			MaxIterations: 3,
+		})
+
+		assert(t, errors.Is(err, ErrNotConverged))
+		assertEqual(t, len(ranks), 11)
+		assertSumsToOne(t, ranks)
+	}
+}
+
+func TestRankContextShouldStopWhenTheContextIsCancelled(t *testing.T) {
+	for _, pageRank := range []Interface{New(), NewConcurrentWithWorkers(4)} {
+		wikipediaExample(pageRank)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		ranks, _, err := pageRank.RankContext(ctx, RankOptions{
+			FollowingProb: 0.85,
+			Tolerance:     0,
+		})
+
+		assert(t, errors.Is(err, context.Canceled))
+		assertEqual(t, len(ranks), 11)
+		assertSumsToOne(t, ranks)
+	}
+}
+
+func TestRankContextOnAnEmptyGraph(t *testing.T) {
+	ranks, _, err := New().RankContext(context.Background(), RankOptions{
+		FollowingProb: 0.85,
+		Tolerance:     0.0001,
+		MaxIterations: 1,
+	})
+
+	assertEqual(t, err, nil)
+	assertEqual(t, len(ranks), 0)
+}
+
+func TestRankShouldReportConvergenceStats(t *testing.T) {
+	const tolerance = 0.0001
+
+	for _, pageRank := range []Interface{New(), NewConcurrentWithWorkers(4)} {
+		wikipediaExample(pageRank)
+
+		stats := pageRank.Rank(0.85, tolerance, func(_ int, _ float64) {})
+
+		assert(t, stats.Iterations > 0)
+		assertEqual(t, len(stats.Residuals), stats.Iterations)
+		assertEqual(t, len(stats.IterationTimes), stats.Iterations)
+		assertEqual(t, stats.Residual, stats.Residuals[stats.Iterations-1])
+		assert(t, stats.Residual <= tolerance)
+		for _, residual := range stats.Residuals[:stats.Iterations-1] {
+			assert(t, residual > tolerance)
+		}
+		//node 0 is the only node without outbound links
+		assertEqual(t, stats.DanglingNodes, 1)
+	}
+}
+
+func TestRankContextShouldReportStatsWhenStoppedEarly(t *testing.T) {
+	pageRank := New()
+	wikipediaExample(pageRank)
+
+	_, stats, err := pageRank.RankContext(context.Background(), RankOptions{
+		FollowingProb: 0.85,
+		Tolerance:     0,
+		MaxIterations: 3,
+	})
+
+	assert(t, errors.Is(err, ErrNotConverged))
+	assertEqual(t, stats.Iterations, 3)
+	assertEqual(t, len(stats.Residuals), 3)
+}
+
+func TestRankShouldStopWhenTheResidualIsNaN(t *testing.T) {
+	sequential, concurrent := New(), NewConcurrentWithWorkers(4)
+	wikipediaExample(sequential)
+	wikipediaExample(concurrent)
+	//a corrupted frozen form makes every rank NaN
+	sequential.Freeze()
+	sequential.frozenForm().inverseOutLinks[1] = math.NaN()
+	concurrent.Freeze()
+	concurrent.frozenForm().inverseOutLinks[1] = math.NaN()
+
+	for _, pageRank := range []Interface{sequential, concurrent} {
+		_, stats, err := pageRank.RankContext(context.Background(), RankOptions{FollowingProb: 0.85, Tolerance: 0.0001})
+
+		assert(t, errors.Is(err, ErrNaN))
+		assertEqual(t, stats.Iterations, 1)
+		assert(t, math.IsNaN(stats.Residual))
+	}
+}
+
+func TestRankShouldBeSafeToCallConcurrently(t *testing.T) {
+	for _, pageRank := range []Interface{New(), NewConcurrentWithWorkers(4)} {
+		buildTestGraph(pageRank, 2*parallelizationThreshold)
+		opts := RankOptions{FollowingProb: 0.85, Tolerance: 1e-8}
+		expected, _, _ := pageRank.RankInto(context.Background(), nil, opts)
+
+		var wg sync.WaitGroup
+		results := make([][]float64, 8)
+		for g := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[g], _, _ = pageRank.RankInto(context.Background(), nil, opts)
+			}()
+		}
+		wg.Wait()
+
+		for _, ranks := range results {
+			for i := range expected {
+				assertEqual(t, ranks[i], expected[i])
+			}
+		}
+	}
+}
+
+func TestWarmStartShouldConvergeFasterToTheSameRanks(t *testing.T) {
+	for _, pageRank := range []Interface{New(), NewConcurrentWithWorkers(4)} {
+		wikipediaExample(pageRank)
+		pageRank.Rank(0.85, 0.000001, func(_ int, _ float64) {})
+
+		pageRank.Link(2, 11)
+		pageRank.Link(11, 4)
+
+		warm, warmStats, err := pageRank.RankContext(context.Background(), RankOptions{
+			FollowingProb: 0.85,
+			Tolerance:     0.000001,
+			WarmStart:     true,
+		})
+
+		cold, coldStats, _ := pageRank.RankContext(context.Background(), RankOptions{
+			FollowingProb: 0.85,
+			Tolerance:     0.000001,
+		})
+
+		assertEqual(t, err, nil)
+		assert(t, warmStats.Iterations < coldStats.Iterations)
+		assertEqual(t, len(warm), 12)
+		for label, rank := range cold {
+			if math.Abs(rank-warm[label]) > 0.00001 {
+				t.Error("Rank for", label, "should be", rank, "but was", warm[label])
+			}
+		}
+	}
+}
+
+func TestWarmStartShouldNotReuseTheRankOfARemovedNode(t *testing.T) {
+	pageRank := New()
+	wikipediaExample(pageRank)
+	pageRank.Rank(0.85, 0.000001, func(_ int, _ float64) {})
+
+	//100 is newer than the last result and takes the index of 1
+	pageRank.Link(100, 1)
+	pageRank.RemoveNode(1)
+	assertEqual(t, pageRank.lastRanks[pageRank.keyToIndex[100]], 0.0)
+
+	warm, _, err := pageRank.RankContext(context.Background(), RankOptions{
+		FollowingProb: 0.85,
+		Tolerance:     0.000001,
+		WarmStart:     true,
+	})
+	cold, _, _ := pageRank.RankContext(context.Background(), RankOptions{
+		FollowingProb: 0.85,
+		Tolerance:     0.000001,
+	})
+
+	assertEqual(t, err, nil)
+	assertEqual(t, len(warm), len(cold))
+	for label, rank := range cold {
+		if math.Abs(rank-warm[label]) > 0.00001 {
+			t.Error("Rank for", label, "should be", rank, "but was", warm[label])
+		}
+	}
+}
+
+func TestInitialRanksShouldSeedTheIteration(t *testing.T) {
+	pageRank := New()
+	wikipediaExample(pageRank)
+
+	converged, coldStats, _ := pageRank.RankContext(context.Background(), RankOptions{
+		FollowingProb: 0.85,
+		Tolerance:     0.000001,
+	})
+
+	//node 99 is unknown and 10 is missing from the prior, both must be ignored or defaulted
+	prior := make(map[int]float64)
+	for label, rank := range converged {
+		prior[label] = rank
+	}
+	delete(prior, 10)
+	prior[99] = 0.5
+
+	ranks, stats, err := pageRank.RankContext(context.Background(), RankOptions{
+		FollowingProb: 0.85,
+		Tolerance:     0.000001,
+		Initial:       prior,
+	})
+
+	assertEqual(t, err, nil)
+	assert(t, stats.Iterations < coldStats.Iterations)
+	assertEqual(t, len(ranks), 11)
+	assertSumsToOne(t, ranks)
+	for label, rank := range converged {
+		if math.Abs(rank-ranks[label]) > 0.00001 {
+			t.Error("Rank for", label, "should be", rank, "but was", ranks[label])
+		}
+	}
+}
+
+func TestInitialRanksShouldIgnoreNonFiniteValues(t *testing.T) {
+	pageRank := New()
+	wikipediaExample(pageRank)
+
+	expected, _, _ := pageRank.RankContext(context.Background(), RankOptions{FollowingProb: 0.85, Tolerance: 0.000001})
+	ranks, _, err := pageRank.RankContext(context.Background(), RankOptions{
+		FollowingProb: 0.85,
+		Tolerance:     0.000001,
+		Initial:       map[int]float64{0: math.Inf(1), 1: 0.5},
+	})
+
+	assertEqual(t, err, nil)
+	assertSumsToOne(t, ranks)
+	for label, rank := range expected {
+		if math.Abs(rank-ranks[label]) > 0.00001 {
+			t.Error("Rank for", label, "should be", rank, "but was", ranks[label])
+		}
+	}
+}
+
+func TestWarmStartShouldResumeAnInterruptedRank(t *testing.T) {
+	pageRank := New()
+	wikipediaExample(pageRank)
+
+	_, _, err := pageRank.RankContext(context.Background(), RankOptions{
+		FollowingProb: 0.85,
+		Tolerance:     0.000001,
+		MaxIterations: 5,
+	})
+	assert(t, errors.Is(err, ErrNotConverged))
+
+	_, stats, err := pageRank.RankContext(context.Background(), RankOptions{
+		FollowingProb: 0.85,
+		Tolerance:     0.000001,
+		WarmStart:     true,
+	})
+	assertEqual(t, err, nil)
+
+	_, coldStats, _ := pageRank.RankContext(context.Background(), RankOptions{
+		FollowingProb: 0.85,
+		Tolerance:     0.000001,
+	})
+	assert(t, stats.Iterations < coldStats.Iterations)
+}
+
+func TestSolversShouldConvergeToThePowerIterationRanks(t *testing.T) {
+	const n = 2 * parallelizationThreshold
+
+	for _, pageRank := range []Interface{New(), NewConcurrentWithWorkers(4)} {
+		buildTestGraph(pageRank, n)
+
+		expected, powerStats, _ := pageRank.RankContext(context.Background(), RankOptions{
+			FollowingProb: 0.85,
+			Tolerance:     1e-10,
+		})
+
+		for _, opts := range []RankOptions{
+			{Solver: GaussSeidel},
+			{Solver: SOR, Omega: 1.05},
+		} {
+			opts.FollowingProb = 0.85
+			opts.Tolerance = 1e-10
+
+			ranks, stats, err := pageRank.RankContext(context.Background(), opts)
+
+			assertEqual(t, err, nil)
+			assert(t, stats.Iterations < powerStats.Iterations)
+			assertSumsToOne(t, ranks)
+			for label, rank := range expected {
+				if math.Abs(rank-ranks[label]) > 1e-9 {
+					t.Error(opts.Solver, "rank for", label, "should be", rank, "but was", ranks[label])
+				}
+			}
+		}
+	}
+}
+
+func TestExtrapolationShouldConvergeFasterToThePowerIterationRanks(t *testing.T) {
+	const n = 2 * parallelizationThreshold
+
+	for _, pageRank := range []Interface{New(), NewConcurrentWithWorkers(4)} {
+		buildTestGraph(pageRank, n)
+
+		expected, powerStats, _ := pageRank.RankContext(context.Background(), RankOptions{
+			FollowingProb: 0.85,
+			Tolerance:     1e-10,
+		})
+
+		for _, extrapolation := range []Extrapolation{Aitken, Quadratic} {
+			ranks, stats, err := pageRank.RankContext(context.Background(), RankOptions{
+				FollowingProb: 0.85,
+				Tolerance:     1e-10,
+				Extrapolation: extrapolation,
+			})
+
+			assertEqual(t, err, nil)
+			assert(t, stats.Extrapolations > 0)
+			assert(t, stats.Iterations < powerStats.Iterations)
+			assertSumsToOne(t, ranks)
+			for label, rank := range expected {
+				if math.Abs(rank-ranks[label]) > 1e-9 {
+					t.Error(extrapolation, "rank for", label, "should be", rank, "but was", ranks[label])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package pagerank
+
+import (
+	"context"
+	"iter"
+	"sort"
+)
+
+// Ranks es el resultado de un cálculo de PageRank. Es una copia: no cambia
+// aunque el grafo se modifique después. Los índices para Get y el orden se
+// construyen en la primera consulta, así que no debe consultarse desde varias
+// goroutines a la vez.
+type Ranks[K comparable] struct {
+	values []float64 // rank por índice interno
+	keys   []K       // clave por índice interno
+
+	index     map[K]int // clave -> índice, se construye en el primer Get
+	order     []int     // índices de mayor a menor rank, en el primer Sorted
+	ascending []float64 // ranks de menor a mayor, para Percentile
+}
+
+// RankedNode es un nodo con su rank
+type RankedNode[K comparable] struct {
+	Key  K
+	Rank float64
+}
+
+func newRanks[K comparable](p []float64, indexToKey []K) *Ranks[K] {
+	return &Ranks[K]{
+		values: append([]float64(nil), p...),
+		keys:   append([]K(nil), indexToKey[:len(p)]...),
+	}
+}
+
+// Len devuelve el número de nodos
+func (r *Ranks[K]) Len() int {
+	return len(r.values)
+}
+
+// Get devuelve el rank de key y si el nodo existe
+func (r *Ranks[K]) Get(key K) (float64, bool) {
+	if r.index == nil {
+		r.index = make(map[K]int, len(r.keys))
+		for i, k := range r.keys {
+			r.index[k] = i
+		}
+	}
+
+	i, ok := r.index[key]
+	if !ok {
+		return 0, false
+	}
+	return r.values[i], true
+}
+
+// TopK devuelve los k nodos de mayor rank en orden descendente (a igual rank,
+// el de menor índice interno primero; RemoveNode y Reorder cambian esos
+// índices, así que no es el orden en que se agregaron). Usa un montículo de
+// tamaño k, así que cuesta O(n log k) sin ordenar todo el resultado.
+func (r *Ranks[K]) TopK(k int) []RankedNode[K] {
+	if r.order != nil {
+		if k > len(r.order) {
+			k = len(r.order)
+		}
+		return r.nodes(r.order[:max(k, 0)])
+	}
+	return r.nodes(topIndices(r.values, k))
+}
+
+// Sorted devuelve todos los nodos de mayor a menor rank
+func (r *Ranks[K]) Sorted() []RankedNode[K] {
+	return r.nodes(r.sortedOrder())
+}
+
+// Percentile devuelve el percentil de rank de key entre 0 y 100: el
+// porcentaje de nodos con rank menor, contando la mitad de los empatados
+func (r *Ranks[K]) Percentile(key K) (float64, bool) {
+	rank, ok := r.Get(key)
+	if !ok {
+		return 0, false
+	}
+
+	if r.ascending == nil {
+		order := r.sortedOrder()
+		r.ascending = make([]float64, len(order))
+		for i, index := range order {
+			r.ascending[len(order)-1-i] = r.values[index]
+		}
+	}
+
+	below := sort.SearchFloat64s(r.ascending, rank)
+	equal := sort.Search(len(r.ascending)-below, func(i int) bool {
+		return r.ascending[below+i] > rank
+	})
+
+	return 100 * (float64(below) + 0.5*float64(equal)) / float64(len(r.ascending)), true
+}
+
+// Range llama a fn con cada nodo de mayor a menor rank hasta que fn devuelva
+// false
+func (r *Ranks[K]) Range(fn func(key K, rank float64) bool) {
+	for _, index := range r.sortedOrder() {
+		if !fn(r.keys[index], r.values[index]) {
+			return
+		}
+	}
+}
+
+// All recorre los nodos de mayor a menor rank:
+//
+//	for key, rank := range ranks.All() { ... }
+func (r *Ranks[K]) All() iter.Seq2[K, float64] {
+	return r.Range
+}
+
+// Map devuelve los ranks como mapa clave -> rank
+func (r *Ranks[K]) Map() map[K]float64 {
+	ranks := make(map[K]float64, len(r.values))
+	for i, rank := range r.values {
+		ranks[r.keys[i]] = rank
+	}
+	return ranks
+}
+
+func (r *Ranks[K]) sortedOrder() []int {
+	if r.order == nil {
+		r.order = make([]int, len(r.values))
+		for i := range r.order {
+			r.order[i] = i
+		}
+		sort.SliceStable(r.order, func(a, b int) bool {
+			return r.values[r.order[a]] > r.values[r.order[b]]
+		})
+	}
+	return r.order
+}
+
+func (r *Ranks[K]) nodes(indices []int) []RankedNode[K] {
+	nodes := make([]RankedNode[K], len(indices))
+	for i, index := range indices {
+		nodes[i] = RankedNode[K]{Key: r.keys[index], Rank: r.values[index]}
+	}
+	return nodes
+}
+
+// rankResults calcula el PageRank según opts y lo devuelve como Ranks
+func (g *graph[K]) rankResults(ctx context.Context, e engine, opts RankOptionsOf[K]) (*Ranks[K], RankStats, error) {
+	p, stats, err := g.rank(ctx, e, opts, nil)
+	return newRanks(p, g.indexToKey), stats, err
+}
+
+// rankSeq calcula el PageRank y lo devuelve como secuencia clave -> rank en
+// el orden interno de los nodos, igual que el callback de Rank
+func (g *graph[K]) rankSeq(e engine, opts RankOptionsOf[K]) (iter.Seq2[K, float64], RankStats) {
+	p, stats, _ := g.rank(context.Background(), e, opts, nil)
+	keys := append([]K(nil), g.indexToKey[:len(p)]...)
+
+	return func(yield func(K, float64) bool) {
+		for i, rank := range p {
+			if !yield(keys[i], rank) {
+				return
+			}
+		}
+	}, stats
+}
+
+// Key devuelve la clave del nodo con el índice interno dado, para leer el
+// resultado de RankInto
+func (g *graph[K]) Key(index int) K {
+	return g.indexToKey[index]
+}
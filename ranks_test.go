@@ -0,0 +1,187 @@
+package pagerank
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"testing"
+)
+
+func TestRanksShouldMatchRankContext(t *testing.T) {
+	for _, pageRank := range []Interface{New(), NewConcurrentWithWorkers(4)} {
+		wikipediaExample(pageRank)
+		opts := RankOptions{FollowingProb: 0.85, Tolerance: 0.0001}
+
+		expected, _, _ := pageRank.RankContext(context.Background(), opts)
+		ranks, _, err := pageRank.RankResults(context.Background(), opts)
+
+		assertEqual(t, err, nil)
+		assertEqual(t, ranks.Len(), len(expected))
+		for label, rank := range expected {
+			actual, ok := ranks.Get(label)
+			assert(t, ok)
+			assertEqual(t, actual, rank)
+		}
+
+		_, ok := ranks.Get(100)
+		assert(t, !ok)
+	}
+}
+
+func TestTopKShouldBeThePrefixOfSorted(t *testing.T) {
+	pageRank := New()
+	buildTestGraph(pageRank, 2000)
+	ranks, _, _ := pageRank.RankResults(context.Background(), RankOptions{FollowingProb: 0.85, Tolerance: 1e-8})
+
+	sorted := ranks.Sorted()
+	assertEqual(t, len(sorted), 2000)
+	for i := 1; i < len(sorted); i++ {
+		assert(t, sorted[i-1].Rank >= sorted[i].Rank)
+	}
+
+	top := ranks.TopK(25)
+	assertEqual(t, len(top), 25)
+	for i, node := range top {
+		assertEqual(t, node, sorted[i])
+	}
+
+	// Con el orden ya calculado TopK lo reutiliza
+	assertEqual(t, ranks.TopK(25)[24], sorted[24])
+	assertEqual(t, len(ranks.TopK(5000)), 2000)
+	assertEqual(t, len(ranks.TopK(0)), 0)
+}
+
+func TestPercentileShouldPlaceTheNodeAmongAllRanks(t *testing.T) {
+	pageRank := New()
+	//star graph: 0 is the center, 1..4 are symmetric
+	for i := 1; i <= 4; i++ {
+		pageRank.Link(i, 0)
+	}
+	ranks, _, _ := pageRank.RankResults(context.Background(), RankOptions{FollowingProb: 0.85, Tolerance: 1e-8})
+
+	percentile, ok := ranks.Percentile(0)
+	assert(t, ok)
+	assertEqual(t, percentile, 90.0)
+
+	percentile, _ = ranks.Percentile(3)
+	assertEqual(t, percentile, 40.0)
+
+	_, ok = ranks.Percentile(7)
+	assert(t, !ok)
+}
+
+func TestRangeShouldVisitTheNodesInRankOrder(t *testing.T) {
+	pageRank := New()
+	wikipediaExample(pageRank)
+	ranks, _, _ := pageRank.RankResults(context.Background(), RankOptions{FollowingProb: 0.85, Tolerance: 0.0001})
+
+	previous := math.Inf(1)
+	visited := 0
+	ranks.Range(func(label int, rank float64) bool {
+		assert(t, rank <= previous)
+		previous = rank
+		visited++
+		return visited < 3
+	})
+
+	assertEqual(t, visited, 3)
+	assertEqual(t, ranks.TopK(1)[0].Key, 1)
+}
+
+func TestRanksShouldNotChangeWhenTheGraphDoes(t *testing.T) {
+	pageRank := New()
+	wikipediaExample(pageRank)
+	ranks, _, _ := pageRank.RankResults(context.Background(), RankOptions{FollowingProb: 0.85, Tolerance: 0.0001})
+	expected := ranks.Map()
+
+	pageRank.RemoveNode(0)
+	pageRank.ApplyLinks([][2]int{{2, 3}, {3, 9}})
+
+	assertEqual(t, ranks.Len(), len(expected))
+	for label, rank := range expected {
+		actual, _ := ranks.Get(label)
+		assertEqual(t, actual, rank)
+	}
+}
+
+func TestRankSeqShouldYieldTheSameRanksAsRank(t *testing.T) {
+	for _, pageRank := range []Interface{New(), NewConcurrentWithWorkers(4)} {
+		wikipediaExample(pageRank)
+
+		expected := make(map[int]float64)
+		pageRank.Rank(0.85, 0.0001, func(label int, rank float64) {
+			expected[label] = rank
+		})
+
+		ranks, stats := pageRank.RankSeq(0.85, 0.0001)
+		assert(t, stats.Iterations > 0)
+
+		count := 0
+		for label, rank := range ranks {
+			assertEqual(t, rank, expected[label])
+			count++
+		}
+		assertEqual(t, count, len(expected))
+
+		for range ranks {
+			break
+		}
+	}
+}
+
+func TestAllShouldIterateInRankOrder(t *testing.T) {
+	pageRank := New()
+	buildTestGraph(pageRank, 500)
+	ranks, _, _ := pageRank.RankResults(context.Background(), RankOptions{FollowingProb: 0.85, Tolerance: 1e-8})
+
+	sorted := ranks.Sorted()
+	i := 0
+	for label, rank := range ranks.All() {
+		assertEqual(t, label, sorted[i].Key)
+		assertEqual(t, rank, sorted[i].Rank)
+		i++
+	}
+	assertEqual(t, i, len(sorted))
+}
+
+func TestRankIntoShouldReuseTheDestination(t *testing.T) {
+	for _, pageRank := range []Interface{New(), NewConcurrentWithWorkers(4)} {
+		wikipediaExample(pageRank)
+		opts := RankOptions{FollowingProb: 0.85, Tolerance: 0.0001}
+
+		expected, _, _ := pageRank.RankContext(context.Background(), opts)
+
+		dst := make([]float64, 0, 64)
+		ranks, _, err := pageRank.RankInto(context.Background(), dst, opts)
+
+		assertEqual(t, err, nil)
+		assertEqual(t, len(ranks), len(expected))
+		assertEqual(t, &ranks[0], &dst[:1][0])
+		for i, rank := range ranks {
+			assertEqual(t, rank, expected[pageRank.Key(i)])
+		}
+	}
+}
+
+func TestRankIntoShouldNotAllocateRankVectors(t *testing.T) {
+	const n = 4 * parallelizationThreshold
+
+	for _, pageRank := range []Interface{New(), NewConcurrentWithWorkers(4)} {
+		buildTestGraph(pageRank, n)
+		opts := RankOptions{FollowingProb: 0.85, Tolerance: 1e-8, MaxIterations: 100}
+
+		dst, _, _ := pageRank.RankInto(context.Background(), nil, opts)
+
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		_, stats, _ := pageRank.RankInto(context.Background(), dst, opts)
+		runtime.ReadMemStats(&after)
+
+		// Un vector ocupa 8*n bytes; por paso sólo crecen las estadísticas
+		perIteration := (after.TotalAlloc - before.TotalAlloc) / uint64(stats.Iterations)
+		assert(t, stats.Iterations > 10)
+		if perIteration > 256 {
+			t.Error("RankInto should not allocate rank vectors but allocated", perIteration, "bytes per iteration")
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package pagerank
+
+import (
+	"math"
+	"sort"
+)
+
+// Ordering es una renumeración de los nodos que acerca en memoria los nodos
+// que se leen juntos, para que el acceso a p[index] de cada iteración
+// aproveche la caché
+type Ordering int
+
+const (
+	// NoOrdering conserva el orden de llegada de las claves
+	NoOrdering Ordering = iota
+	// DegreeOrdering pone primero los nodos con más enlaces (entrantes más
+	// salientes), así los ranks que más se leen comparten líneas de caché
+	DegreeOrdering
+	// RCMOrdering es Reverse Cuthill-McKee: un recorrido en anchura desde el
+	// nodo de menor grado de cada componente, visitando los vecinos de menor
+	// a mayor grado, en orden inverso. Deja cerca a los vecinos.
+	RCMOrdering
+	// GorderOrdering coloca uno a uno el nodo que más vecinos y hermanos
+	// (nodos con un origen en común) comparte con los últimos gorderWindow
+	// colocados, como Gorder (Wei et al., 2016)
+	GorderOrdering
+)
+
+// gorderWindow es la cantidad de nodos recién colocados que puntúan al
+// siguiente en GorderOrdering
+const gorderWindow = 5
+
+func (o Ordering) String() string {
+	switch o {
+	case DegreeOrdering:
+		return "degree"
+	case RCMOrdering:
+		return "rcm"
+	case GorderOrdering:
+		return "gorder"
+	default:
+		return "none"
+	}
+}
+
+// Reorder renumera los índices internos de los nodos según ordering. Las
+// claves, los ranks y el último resultado no cambian; sólo cambian los
+// índices de RankInto, que se traducen con Key. El grafo queda congelado si
+// lo estaba y, si no, con listas de adyacencia en el nuevo orden.
+func (g *graph[K]) Reorder(ordering Ordering) {
+	if ordering == NoOrdering || len(g.indexToKey) == 0 {
+		return
+	}
+
+	c := g.frozenForm()
+	c.buildOutLinks()
+
+	var order []int
+	switch ordering {
+	case DegreeOrdering:
+		order = degreeOrder(c)
+	case RCMOrdering:
+		order = rcmOrder(c)
+	default:
+		order = gorderOrder(c)
+	}
+
+	g.relabel(order)
+}
+
+// relabel aplica la renumeración order (order[nuevo] = viejo) a la forma CSR,
+// a las claves y al último resultado
+func (g *graph[K]) relabel(order []int) {
+	c := g.csr
+	size := c.size()
+	newIndex := make([]int, size)
+	for i, old := range order {
+		newIndex[old] = i
+	}
+
+	relabeled := &csr{
+		offsets:         make([]int, size+1),
+		inLinks:         make([]uint32, 0, len(c.inLinks)),
+		inverseOutLinks: make([]float64, size),
+	}
+	if c.weights != nil {
+		relabeled.weights = make([]float64, 0, len(c.inLinks))
+	}
+
+	for i, old := range order {
+		start, end := c.offsets[old], c.offsets[old+1]
+		row := relabeled.inLinks[len(relabeled.inLinks) : len(relabeled.inLinks)+end-start]
+		for j, from := range c.inLinks[start:end] {
+			row[j] = uint32(newIndex[from])
+		}
+		relabeled.inLinks = relabeled.inLinks[:len(relabeled.inLinks)+end-start]
+
+		if c.weights != nil {
+			relabeled.weights = append(relabeled.weights, c.weights[start:end]...)
+			// Los orígenes en orden creciente recorren p hacia adelante
+			sort.Sort(weightedRow{row, relabeled.weights[len(relabeled.weights)-len(row):]})
+		} else {
+			sort.Slice(row, func(a, b int) bool { return row[a] < row[b] })
+		}
+
+		relabeled.offsets[i+1] = len(relabeled.inLinks)
+		relabeled.inverseOutLinks[i] = c.inverseOutLinks[old]
+		if c.inverseOutLinks[old] == 0 {
+			relabeled.danglingNodes = append(relabeled.danglingNodes, uint32(i))
+		}
+	}
+
+	indexToKey := make([]K, size)
+	for i, old := range order {
+		indexToKey[i] = g.indexToKey[old]
+		g.keyToIndex[indexToKey[i]] = i
+	}
+	g.indexToKey = indexToKey
+
+	if g.lastRanks != nil {
+		lastRanks := make([]float64, size)
+		for old, rank := range g.lastRanks[:min(len(g.lastRanks), size)] {
+			lastRanks[newIndex[old]] = rank
+		}
+		g.lastRanks = lastRanks
+	}
+
+	g.csr = relabeled
+	g.outLinks = nil
+	if !g.frozen {
+		g.thaw()
+	}
+}
+
+// weightedRow ordena una fila de inLinks junto con sus pesos
+type weightedRow struct {
+	links   []uint32
+	weights []float64
+}
+
+func (r weightedRow) Len() int           { return len(r.links) }
+func (r weightedRow) Less(a, b int) bool { return r.links[a] < r.links[b] }
+func (r weightedRow) Swap(a, b int) {
+	r.links[a], r.links[b] = r.links[b], r.links[a]
+	r.weights[a], r.weights[b] = r.weights[b], r.weights[a]
+}
+
+// degree es la cantidad de enlaces entrantes más salientes de i
+func (c *csr) degree(i int) int {
+	return c.offsets[i+1] - c.offsets[i] + c.outOffsets[i+1] - c.outOffsets[i]
+}
+
+// neighbors recorre los orígenes y los destinos de los enlaces de i
+func (c *csr) neighbors(i int, visit func(neighbor int)) {
+	for _, from := range c.inLinks[c.offsets[i]:c.offsets[i+1]] {
+		visit(int(from))
+	}
+	for _, to := range c.outLinks[c.outOffsets[i]:c.outOffsets[i+1]] {
+		visit(int(to))
+	}
+}
+
+// identity devuelve los índices 0..size-1
+func identity(size int) []int {
+	order := make([]int, size)
+	for i := range order {
+		order[i] = i
+	}
+	return order
+}
+
+func degreeOrder(c *csr) []int {
+	order := identity(c.size())
+	sort.SliceStable(order, func(a, b int) bool {
+		return c.degree(order[a]) > c.degree(order[b])
+	})
+	return order
+}
+
+func rcmOrder(c *csr) []int {
+	starts := identity(c.size())
+	sort.SliceStable(starts, func(a, b int) bool {
+		return c.degree(starts[a]) < c.degree(starts[b])
+	})
+
+	visited := make([]bool, c.size())
+	order := make([]int, 0, c.size())
+	for _, start := range starts {
+		if visited[start] {
+			continue
+		}
+		visited[start] = true
+		order = append(order, start)
+
+		// order hace de cola del recorrido en anchura
+		for head := len(order) - 1; head < len(order); head++ {
+			first := len(order)
+			c.neighbors(order[head], func(neighbor int) {
+				if !visited[neighbor] {
+					visited[neighbor] = true
+					order = append(order, neighbor)
+				}
+			})
+			level := order[first:]
+			sort.SliceStable(level, func(a, b int) bool {
+				return c.degree(level[a]) < c.degree(level[b])
+			})
+		}
+	}
+
+	for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+		order[i], order[j] = order[j], order[i]
+	}
+	return order
+}
+
+func gorderOrder(c *csr) []int {
+	size := c.size()
+	queue := newScoreQueue(size)
+	placed := make([]bool, size)
+
+	// Los hermanos de un origen con muchos enlaces salientes son casi todo
+	// el grafo: no suman puntos, como en Gorder
+	hubOutLinks := int(math.Sqrt(float64(size)))
+	score := func(u, delta int) {
+		bump := func(x int) {
+			if !placed[x] {
+				queue.add(x, delta)
+			}
+		}
+		for _, to := range c.outLinks[c.outOffsets[u]:c.outOffsets[u+1]] {
+			bump(int(to))
+		}
+		for _, from := range c.inLinks[c.offsets[u]:c.offsets[u+1]] {
+			bump(int(from))
+			first, last := c.outOffsets[from], c.outOffsets[from+1]
+			if last-first > hubOutLinks {
+				continue
+			}
+			for _, sibling := range c.outLinks[first:last] {
+				if int(sibling) != u {
+					bump(int(sibling))
+				}
+			}
+		}
+	}
+
+	// Sin candidatos con puntos se sigue por el nodo libre de mayor grado
+	seeds := degreeOrder(c)
+	nextSeed := 0
+
+	order := make([]int, 0, size)
+	for len(order) < size {
+		u, ok := queue.popMax()
+		if !ok {
+			for placed[seeds[nextSeed]] {
+				nextSeed++
+			}
+			u = seeds[nextSeed]
+			queue.remove(u)
+		}
+		placed[u] = true
+		order = append(order, u)
+
+		score(u, 1)
+		if len(order) > gorderWindow {
+			score(order[len(order)-1-gorderWindow], -1)
+		}
+	}
+
+	return order
+}
+
+// scoreQueue es una cola de prioridad de puntajes enteros pequeños con suma
+// y resta en O(1) (la unit heap de Gorder): un bucket por puntaje, como listas
+// doblemente enlazadas sobre arreglos.
+type scoreQueue struct {
+	score      []int
+	prev, next []int // -1 al principio y al final de cada bucket
+	buckets    []int // primer nodo de cada puntaje, -1 si está vacío
+	top        int   // cota superior del mayor puntaje no vacío
+}
+
+// newScoreQueue crea la cola con los nodos 0..size-1 con puntaje 0
+func newScoreQueue(size int) *scoreQueue {
+	q := &scoreQueue{
+		score:   make([]int, size),
+		prev:    make([]int, size),
+		next:    make([]int, size),
+		buckets: []int{-1},
+	}
+	for x := size - 1; x >= 0; x-- {
+		q.push(x)
+	}
+	return q
+}
+
+func (q *scoreQueue) push(x int) {
+	s := q.score[x]
+	for len(q.buckets) <= s {
+		q.buckets = append(q.buckets, -1)
+	}
+	q.prev[x], q.next[x] = -1, q.buckets[s]
+	if q.buckets[s] >= 0 {
+		q.prev[q.buckets[s]] = x
+	}
+	q.buckets[s] = x
+	q.top = max(q.top, s)
+}
+
+// remove saca a x de la cola
+func (q *scoreQueue) remove(x int) {
+	if q.prev[x] >= 0 {
+		q.next[q.prev[x]] = q.next[x]
+	} else {
+		q.buckets[q.score[x]] = q.next[x]
+	}
+	if q.next[x] >= 0 {
+		q.prev[q.next[x]] = q.prev[x]
+	}
+}
+
+// add suma delta al puntaje de x, que debe estar en la cola
+func (q *scoreQueue) add(x, delta int) {
+	q.remove(x)
+	q.score[x] += delta
+	q.push(x)
+}
+
+// popMax saca el nodo de mayor puntaje, o false si ninguno tiene puntos
+func (q *scoreQueue) popMax() (int, bool) {
+	for q.top > 0 && q.buckets[q.top] < 0 {
+		q.top--
+	}
+	if q.top == 0 {
+		return 0, false
+	}
+	x := q.buckets[q.top]
+	q.remove(x)
+	return x, true
+}
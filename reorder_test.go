@@ -0,0 +1,163 @@
+package pagerank
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+var orderings = []Ordering{DegreeOrdering, RCMOrdering, GorderOrdering}
+
+// shuffledGrid es una grilla de side x side con enlaces en ambos sentidos
+// cargados en orden aleatorio, así el orden de llegada de las claves no tiene
+// ninguna localidad
+func shuffledGrid(pageRank Interface, side int) {
+	random := rand.New(rand.NewSource(42))
+	keys := random.Perm(side * side)
+	var edges [][2]int
+	for row := 0; row < side; row++ {
+		for col := 0; col < side; col++ {
+			node := keys[row*side+col]
+			if col+1 < side {
+				edges = append(edges, [2]int{node, keys[row*side+col+1]}, [2]int{keys[row*side+col+1], node})
+			}
+			if row+1 < side {
+				edges = append(edges, [2]int{node, keys[(row+1)*side+col]}, [2]int{keys[(row+1)*side+col], node})
+			}
+		}
+	}
+	random.Shuffle(len(edges), func(i, j int) {
+		edges[i], edges[j] = edges[j], edges[i]
+	})
+	pageRank.AddEdges(edges)
+}
+
+// averageGap es la distancia media entre los índices de los extremos de
+// cada enlace
+func averageGap(c *csr) float64 {
+	total := 0
+	for i := 0; i < c.size(); i++ {
+		for _, from := range c.inLinks[c.offsets[i]:c.offsets[i+1]] {
+			total += max(i-int(from), int(from)-i)
+		}
+	}
+	return float64(total) / float64(len(c.inLinks))
+}
+
+func TestOrderingsShouldBePermutations(t *testing.T) {
+	pageRank := New()
+	hubGraph(pageRank, 3000)
+	//a second component and isolated dangling nodes
+	pageRank.Link(5000, 5001)
+	pageRank.Link(5002, 5001)
+	c := pageRank.frozenForm()
+	c.buildOutLinks()
+
+	for _, order := range [][]int{degreeOrder(c), rcmOrder(c), gorderOrder(c)} {
+		assertEqual(t, len(order), c.size())
+		seen := make([]bool, c.size())
+		for _, old := range order {
+			assert(t, !seen[old])
+			seen[old] = true
+		}
+	}
+}
+
+func TestReorderShouldNotChangeTheRanks(t *testing.T) {
+	for _, ordering := range orderings {
+		for _, frozen := range []bool{false, true} {
+			for _, pageRank := range []Interface{New(), NewConcurrentWithWorkers(4)} {
+				expected := New()
+				buildTestGraph(expected, 3*parallelizationThreshold)
+				expected.LinkWeighted(3, 7, 5)
+
+				buildTestGraph(pageRank, 3*parallelizationThreshold)
+				pageRank.LinkWeighted(3, 7, 5)
+				if frozen {
+					pageRank.Freeze()
+				}
+				pageRank.Reorder(ordering)
+
+				assertSameRanks(t, pageRank, expected)
+				ranks, _, _ := pageRank.RankInto(context.Background(), nil, RankOptions{FollowingProb: 0.85, Tolerance: 1e-12})
+				expectedRanks, _, _ := expected.RankContext(context.Background(), RankOptions{FollowingProb: 0.85, Tolerance: 1e-12})
+				for i, rank := range ranks {
+					if math.Abs(rank-expectedRanks[pageRank.Key(i)]) > 1e-12 {
+						t.Fatal(ordering, "rank for", pageRank.Key(i), "should be", expectedRanks[pageRank.Key(i)], "but was", rank)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestReorderShouldKeepTheGraphUsable(t *testing.T) {
+	pageRank := New()
+	buildTestGraph(pageRank, 1000)
+	pageRank.Reorder(RCMOrdering)
+	assertConsistent(t, &pageRank.graph)
+
+	pageRank.Link(3, 1001)
+	pageRank.Unlink(11, (11*7+13+1)%1000)
+	pageRank.RemoveNode(500)
+
+	expected := New()
+	buildTestGraph(expected, 1000)
+	expected.Link(3, 1001)
+	expected.Unlink(11, (11*7+13+1)%1000)
+	expected.RemoveNode(500)
+
+	assertConsistent(t, &pageRank.graph)
+	assertSameRanks(t, pageRank, expected)
+}
+
+func TestReorderShouldKeepTheLastResult(t *testing.T) {
+	pageRank := New()
+	buildTestGraph(pageRank, 1000)
+	_, stats, _ := pageRank.RankContext(context.Background(), RankOptions{FollowingProb: 0.85, Tolerance: 1e-10})
+	expected := pageRank.CurrentRanks()
+
+	pageRank.Reorder(GorderOrdering)
+
+	actual := pageRank.CurrentRanks()
+	assertEqual(t, len(actual), len(expected))
+	for key, rank := range expected {
+		assertEqual(t, actual[key], rank)
+	}
+
+	//warm start from the relabeled result needs only a few iterations
+	_, warmStats, _ := pageRank.RankContext(context.Background(), RankOptions{FollowingProb: 0.85, Tolerance: 1e-10, WarmStart: true})
+	assert(t, warmStats.Iterations < stats.Iterations/4)
+}
+
+func TestLocalityOrderingsShouldShortenTheLinks(t *testing.T) {
+	pageRank := New()
+	shuffledGrid(pageRank, 100)
+	before := averageGap(pageRank.frozenForm())
+
+	for _, ordering := range []Ordering{RCMOrdering, GorderOrdering} {
+		pageRank.Reorder(ordering)
+		after := averageGap(pageRank.frozenForm())
+
+		t.Log(ordering, "average gap", before, "->", after)
+		assert(t, after < before/10)
+	}
+}
+
+func BenchmarkOrderings(b *testing.B) {
+	for _, ordering := range append([]Ordering{NoOrdering}, orderings...) {
+		pageRank := New()
+		shuffledGrid(pageRank, 500)
+		pageRank.Reorder(ordering)
+		pageRank.Freeze()
+		dst := make([]float64, 500*500)
+
+		b.Run(ordering.String(), func(b *testing.B) {
+			opts := RankOptions{FollowingProb: 0.85, MaxIterations: 20}
+			for i := 0; i < b.N; i++ {
+				dst, _, _ = pageRank.RankInto(context.Background(), dst, opts)
+			}
+		})
+	}
+}
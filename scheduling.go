@@ -0,0 +1,196 @@
+package pagerank
+
+import (
+	"sort"
+	"time"
+)
+
+// Scheduling decide cómo reparte el motor concurrente los nodos entre sus
+// workers. El motor secuencial lo ignora.
+type Scheduling int
+
+const (
+	// EdgeBalanced da a cada worker un rango contiguo con la misma cantidad
+	// de trabajo, contando cada nodo más sus enlaces entrantes. En grafos con
+	// hubs evita que el worker que los recibe frene a todos los demás.
+	EdgeBalanced Scheduling = iota
+	// NodeBalanced da a cada worker la misma cantidad de nodos, sin mirar
+	// los enlaces (el reparto original)
+	NodeBalanced
+	// DynamicScheduling divide el trabajo en blocksPerWorker bloques
+	// balanceados por enlaces por worker, que los workers toman de un
+	// contador atómico a medida que terminan el anterior
+	DynamicScheduling
+)
+
+// blocksPerWorker es la cantidad de bloques por worker de DynamicScheduling
+const blocksPerWorker = 16
+
+func (s Scheduling) String() string {
+	switch s {
+	case NodeBalanced:
+		return "node-balanced"
+	case DynamicScheduling:
+		return "dynamic"
+	default:
+		return "edge-balanced"
+	}
+}
+
+// WorkerLoad es el trabajo que hizo un worker del motor concurrente durante
+// un cálculo, sumando todas las iteraciones
+type WorkerLoad struct {
+	Nodes  int           // Nodos calculados
+	Edges  int           // Enlaces entrantes recorridos
+	Blocks int           // Rangos de nodos tomados
+	Busy   time.Duration // Tiempo trabajando dentro de las fases
+}
+
+// reductionBlockSize es el tamaño de los bloques fijos del modo determinista
+const reductionBlockSize = 4096
+
+// partitionByEdges divide los nodos de c en parts rangos contiguos con el
+// mismo costo, contando cada nodo más sus enlaces entrantes. Como offsets es
+// la suma acumulada de enlaces entrantes, cada frontera es una búsqueda
+// binaria. Un rango puede quedar vacío si un solo nodo supera su parte.
+func partitionByEdges(c *csr, parts int) []workChunk {
+	return balancedRanges(c.size(), parts, func(i int) int {
+		return c.offsets[i] + i
+	})
+}
+
+// balancedRanges divide [0, count) en parts rangos contiguos de costo
+// parecido. prefixCost(i) es el costo de los primeros i elementos y no debe
+// decrecer.
+func balancedRanges(count, parts int, prefixCost func(i int) int) []workChunk {
+	total := prefixCost(count)
+	ranges := make([]workChunk, parts)
+
+	for w := 1; w < parts; w++ {
+		target := total * w / parts
+		boundary := sort.Search(count+1, func(i int) bool {
+			return prefixCost(i) >= target
+		})
+		ranges[w-1].end = boundary
+		ranges[w].start = boundary
+	}
+	ranges[parts-1].end = count
+
+	return ranges
+}
+
+// fixedBlocks corta los nodos en bloques de reductionBlockSize, que no
+// dependen de la cantidad de workers
+func fixedBlocks(size int) []workChunk {
+	blocks := make([]workChunk, (size+reductionBlockSize-1)/reductionBlockSize)
+	for b := range blocks {
+		blocks[b].start = b * reductionBlockSize
+		blocks[b].end = min(blocks[b].start+reductionBlockSize, size)
+	}
+	return blocks
+}
+
+// oneBlockEach asigna a cada worker el bloque con su mismo número
+func oneBlockEach(numWorkers int) []workChunk {
+	owned := make([]workChunk, numWorkers)
+	for w := range owned {
+		owned[w] = workChunk{start: w, end: w + 1}
+	}
+	return owned
+}
+
+// schedulePool crea el pool con el reparto pedido para len(chunks) workers;
+// chunks es el reparto por nodos de calculateWorkChunks. En modo determinista
+// los bloques son siempre los de fixedBlocks y el reparto sólo decide qué
+// worker calcula cada uno.
+func schedulePool(c *csr, chunks []workChunk, options engineOptions) *workerPool {
+	numWorkers := len(chunks)
+
+	if options.deterministic {
+		size := c.size()
+		blocks := fixedBlocks(size)
+
+		switch options.scheduling {
+		case DynamicScheduling:
+			return newWorkerPool(numWorkers, blocks, nil, options, c)
+		case NodeBalanced:
+			return newWorkerPool(numWorkers, blocks, balancedRanges(len(blocks), numWorkers, func(b int) int {
+				return b
+			}), options, c)
+		default:
+			return newWorkerPool(numWorkers, blocks, balancedRanges(len(blocks), numWorkers, func(b int) int {
+				i := min(b*reductionBlockSize, size)
+				return c.offsets[i] + i
+			}), options, c)
+		}
+	}
+
+	if options.kernel == PushKernel {
+		return schedulePushPool(c, chunks, options)
+	}
+
+	switch options.scheduling {
+	case NodeBalanced:
+		return newWorkerPool(numWorkers, chunks, oneBlockEach(numWorkers), options, c)
+	case DynamicScheduling:
+		return newWorkerPool(numWorkers, partitionByEdges(c, numWorkers*blocksPerWorker), nil, options, c)
+	default:
+		return newWorkerPool(numWorkers, partitionByEdges(c, numWorkers), oneBlockEach(numWorkers), options, c)
+	}
+}
+
+// schedulePushPool es schedulePool para el kernel push. Los orígenes se
+// reparten según sus enlaces salientes; sumar los búferes cuesta lo mismo en
+// cada nodo, así que los destinos se reparten por nodos.
+func schedulePushPool(c *csr, chunks []workChunk, options engineOptions) *workerPool {
+	numWorkers, size := len(chunks), c.size()
+	outCost := func(i int) int {
+		return c.outOffsets[i] + i
+	}
+
+	var pool *workerPool
+	switch options.scheduling {
+	case NodeBalanced:
+		pool = newWorkerPool(numWorkers, chunks, oneBlockEach(numWorkers), options, c)
+		pool.sources = chunks
+	case DynamicScheduling:
+		parts := numWorkers * blocksPerWorker
+		pool = newWorkerPool(numWorkers, balancedRanges(size, parts, func(i int) int {
+			return i
+		}), nil, options, c)
+		pool.sources = balancedRanges(size, parts, outCost)
+	default:
+		pool = newWorkerPool(numWorkers, chunks, oneBlockEach(numWorkers), options, c)
+		pool.sources = balancedRanges(size, numWorkers, outCost)
+	}
+	pool.sourcesOwned = pool.owned
+
+	return pool
+}
+
+// pairwiseSum suma values por pares (el árbol sólo depende de len(values)),
+// con error de redondeo O(log n) en lugar de O(n)
+func pairwiseSum(values []float64) float64 {
+	switch len(values) {
+	case 0:
+		return 0
+	case 1:
+		return values[0]
+	}
+	half := len(values) / 2
+	return pairwiseSum(values[:half]) + pairwiseSum(values[half:])
+}
+
+// pairwiseMerge combina por pares los acumuladores de cambio de cada bloque
+func pairwiseMerge(accs []changeAccumulator) changeAccumulator {
+	switch len(accs) {
+	case 0:
+		return changeAccumulator{}
+	case 1:
+		return accs[0]
+	}
+	half := len(accs) / 2
+	acc := pairwiseMerge(accs[:half])
+	acc.merge(pairwiseMerge(accs[half:]))
+	return acc
+}
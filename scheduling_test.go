@@ -0,0 +1,155 @@
+package pagerank
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+// hubGraph es un grafo con pocos nodos de índice bajo que reciben la mayoría
+// de los enlaces, como los que genera GeneradorGrafos
+func hubGraph(pageRank Interface, n int) {
+	for i := 0; i < n; i++ {
+		pageRank.Link(i, i%50)
+		pageRank.Link(i, i%50+1)
+		pageRank.Link(i, (i*7+1)%n)
+	}
+}
+
+func TestSchedulingsShouldGiveTheSameRanks(t *testing.T) {
+	const n = 4 * parallelizationThreshold
+
+	expected := New()
+	hubGraph(expected, n)
+	expectedRanks, _, _ := expected.RankContext(context.Background(), RankOptions{FollowingProb: 0.85, Tolerance: 1e-10})
+
+	for _, scheduling := range []Scheduling{EdgeBalanced, NodeBalanced, DynamicScheduling} {
+		pageRank := NewConcurrentWithWorkers(4)
+		hubGraph(pageRank, n)
+
+		ranks, stats, err := pageRank.RankContext(context.Background(), RankOptions{
+			FollowingProb: 0.85,
+			Tolerance:     1e-10,
+			Scheduling:    scheduling,
+		})
+
+		assertEqual(t, err, nil)
+		for label, rank := range expectedRanks {
+			if math.Abs(rank-ranks[label]) > 1e-12 {
+				t.Error(scheduling, "rank for", label, "should be", rank, "but was", ranks[label])
+			}
+		}
+
+		nodes, edges, blocks := 0, 0, 0
+		for _, load := range stats.WorkerLoads {
+			nodes += load.Nodes
+			edges += load.Edges
+			blocks += load.Blocks
+			assert(t, load.Busy > 0)
+		}
+		assertEqual(t, len(stats.WorkerLoads), 4)
+		assertEqual(t, nodes, stats.Iterations*n)
+		assertEqual(t, edges, stats.Iterations*3*n)
+		if scheduling == DynamicScheduling {
+			assertEqual(t, blocks, stats.Iterations*4*blocksPerWorker)
+		} else {
+			assertEqual(t, blocks, stats.Iterations*4)
+		}
+	}
+}
+
+func TestEdgeBalancedShouldSpreadTheHubs(t *testing.T) {
+	const n = 4 * parallelizationThreshold
+
+	maxOverMean := func(scheduling Scheduling) float64 {
+		pageRank := NewConcurrentWithWorkers(4)
+		hubGraph(pageRank, n)
+		_, stats, _ := pageRank.RankContext(context.Background(), RankOptions{
+			FollowingProb: 0.85,
+			Tolerance:     1e-6,
+			Scheduling:    scheduling,
+		})
+
+		most, total := 0, 0
+		for _, load := range stats.WorkerLoads {
+			cost := load.Nodes + load.Edges
+			most = max(most, cost)
+			total += cost
+		}
+		return float64(most) / (float64(total) / float64(len(stats.WorkerLoads)))
+	}
+
+	assert(t, maxOverMean(NodeBalanced) > 1.5)
+	assert(t, maxOverMean(EdgeBalanced) < 1.05)
+}
+
+func TestPartitionByEdgesShouldCoverAllNodes(t *testing.T) {
+	pageRank := New()
+	//node 0 gets more in-links than half of the cost
+	for i := 1; i < 100; i++ {
+		for j := 0; j < 3; j++ {
+			pageRank.Link(i, 0)
+		}
+		pageRank.Link(i, i%10+1)
+	}
+	c := pageRank.frozenForm()
+
+	chunks := partitionByEdges(c, 4)
+
+	assertEqual(t, chunks[0].start, 0)
+	assertEqual(t, chunks[3].end, c.size())
+	for w := 1; w < len(chunks); w++ {
+		assertEqual(t, chunks[w].start, chunks[w-1].end)
+	}
+	//the hub closes the first chunk and leaves the second one empty
+	hub := pageRank.keyToIndex[0]
+	assertEqual(t, chunks[0].end, hub+1)
+	assertEqual(t, chunks[1].end, chunks[1].start)
+}
+
+func TestSequentialEngineShouldNotReportWorkerLoads(t *testing.T) {
+	pageRank := New()
+	hubGraph(pageRank, 2*parallelizationThreshold)
+	_, stats, _ := pageRank.RankContext(context.Background(), RankOptions{FollowingProb: 0.85, Tolerance: 1e-6})
+
+	assert(t, stats.WorkerLoads == nil)
+}
+
+func TestDeterministicShouldBeBitIdenticalAcrossWorkers(t *testing.T) {
+	const n = 6 * parallelizationThreshold
+
+	for _, solver := range []Solver{PowerIteration, GaussSeidel} {
+		opts := RankOptions{FollowingProb: 0.85, Tolerance: 1e-10, Solver: solver, Deterministic: true}
+
+		sequential := New()
+		hubGraph(sequential, n)
+		expected, expectedStats, _ := sequential.RankInto(context.Background(), nil, opts)
+
+		for _, workers := range []int{1, 2, 3, 8, 16} {
+			for _, scheduling := range []Scheduling{EdgeBalanced, NodeBalanced, DynamicScheduling} {
+				pageRank := NewConcurrentWithWorkers(workers)
+				hubGraph(pageRank, n)
+				opts.Scheduling = scheduling
+
+				ranks, stats, _ := pageRank.RankInto(context.Background(), nil, opts)
+
+				assertEqual(t, stats.Iterations, expectedStats.Iterations)
+				assertEqual(t, stats.Residual, expectedStats.Residual)
+				for i := range expected {
+					if ranks[i] != expected[i] {
+						t.Fatal(solver, workers, scheduling, "rank for", i, "should be", expected[i], "but was", ranks[i])
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestPairwiseSumShouldOnlyDependOnTheValues(t *testing.T) {
+	values := []float64{1e16, 1, -1e16, 1, 3, 0.5, 0.25}
+
+	v := values
+	assertEqual(t, pairwiseSum(values), (v[0]+(v[1]+v[2]))+((v[3]+v[4])+(v[5]+v[6])))
+	assertEqual(t, pairwiseSum(nil), 0.0)
+	assertEqual(t, pairwiseSum(values[:1]), 1e16)
+}
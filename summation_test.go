@@ -0,0 +1,136 @@
+package pagerank
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestAccumulatorShouldKeepTheSmallTerms(t *testing.T) {
+	plain := accumulator{}
+	compensated := accumulator{compensated: true}
+
+	plain.add(1)
+	compensated.add(1)
+	for i := 0; i < 1000000; i++ {
+		plain.add(1e-16)
+		compensated.add(1e-16)
+	}
+
+	assertEqual(t, plain.value(), 1.0)
+	assert(t, math.Abs(compensated.value()-(1+1e-10)) < 1e-15)
+}
+
+// referenceStep es una iteración de potencia con teletransporte uniforme
+// calculada en punto flotante de 256 bits a partir de los mismos datos que
+// sequentialStep
+func referenceStep(followingProb float64, c *csr, p []float64) []float64 {
+	const prec = 256
+	newFloat := func(x float64) *big.Float {
+		return new(big.Float).SetPrec(prec).SetFloat64(x)
+	}
+
+	uniform := newFloat(1.0 / float64(c.size()))
+	following := newFloat(followingProb)
+	teleporting := newFloat(1)
+	teleporting.Sub(teleporting, following)
+
+	innerProduct := newFloat(0)
+	for _, danglingNode := range c.danglingNodes {
+		innerProduct.Add(innerProduct, newFloat(p[danglingNode]))
+	}
+
+	term := newFloat(0)
+	raw := make([]*big.Float, c.size())
+	vsum := newFloat(0)
+	for i := range raw {
+		ksum := newFloat(0)
+		for _, index := range c.inLinks[c.offsets[i]:c.offsets[i+1]] {
+			term.Mul(newFloat(p[index]), newFloat(c.inverseOutLinks[index]))
+			ksum.Add(ksum, term)
+		}
+		term.Mul(innerProduct, uniform)
+		ksum.Add(ksum, term)
+		ksum.Mul(ksum, following)
+		term.Mul(teleporting, uniform)
+		raw[i] = ksum.Add(ksum, term)
+		vsum.Add(vsum, raw[i])
+	}
+
+	v := make([]float64, c.size())
+	for i := range v {
+		v[i], _ = raw[i].Quo(raw[i], vsum).Float64()
+	}
+	return v
+}
+
+func TestCompensatedSummationShouldBeCloserToTheReference(t *testing.T) {
+	if testing.Short() {
+		t.Skip("grafo grande")
+	}
+	const n = 1 << 19
+
+	pageRank := New()
+	pageRank.AddEdges(testEdges(n))
+	c := pageRank.frozenForm()
+	tp := newTeleport[int](nil, nil, c.size(), DanglingUniform)
+
+	//a rank vector spanning several orders of magnitude
+	p := make([]float64, n)
+	for i := range p {
+		p[i] = math.Pow(10, -float64(i%7)) * float64(i%13+1)
+	}
+	normalize(p)
+
+	expected := referenceStep(0.85, c, p)
+	maxRelativeError := func(v []float64) float64 {
+		worst := 0.0
+		for i := range v {
+			worst = max(worst, math.Abs(v[i]-expected[i])/expected[i])
+		}
+		return worst
+	}
+
+	plain := sequentialStep(0.85, tp, c, p, make([]float64, n), engineOptions{})
+	compensated := sequentialStep(0.85, tp, c, p, make([]float64, n), engineOptions{compensated: true})
+	plainError := maxRelativeError(plain.Current)
+	compensatedError := maxRelativeError(compensated.Current)
+	t.Log("plain", plainError, "compensated", compensatedError)
+
+	assert(t, compensatedError < 1e-15)
+	assert(t, plainError > 10*compensatedError)
+
+	//the concurrent engine must be as accurate, in both modes
+	concurrent := NewConcurrentWithWorkers(4)
+	concurrent.AddEdges(testEdges(n))
+	for _, deterministic := range []bool{false, true} {
+		stop := concurrent.start(c, engineOptions{compensated: true, deterministic: deterministic})
+		step := concurrent.step(0.85, tp, c, p, make([]float64, n))
+		stop(&RankStats{})
+
+		assert(t, maxRelativeError(step.Current) < 1e-15)
+	}
+}
+
+func TestCompensatedSummationShouldGiveTheSameRanks(t *testing.T) {
+	for _, solver := range []Solver{PowerIteration, GaussSeidel} {
+		for _, pageRank := range []Interface{New(), NewConcurrentWithWorkers(4)} {
+			hubGraph(pageRank, 2*parallelizationThreshold)
+			plain, _, _ := pageRank.RankInto(context.Background(), nil, RankOptions{FollowingProb: 0.85, Tolerance: 1e-12, Solver: solver})
+			compensated, _, err := pageRank.RankInto(context.Background(), nil, RankOptions{
+				FollowingProb:        0.85,
+				Tolerance:            1e-12,
+				Solver:               solver,
+				CompensatedSummation: true,
+			})
+
+			assertEqual(t, err, nil)
+			for i := range plain {
+				if math.Abs(plain[i]-compensated[i]) > 1e-12 {
+					t.Fatal(solver, "rank for", i, "should be", plain[i], "but was", compensated[i])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package pagerank
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// workerPool son las goroutines del motor concurrente. Se crean una vez al
+// empezar cada cálculo y se reutilizan en todas las fases de todas las
+// iteraciones: el hilo que itera publica la fase, hace la parte del worker 0
+// y espera en la barrera a los demás. Los parámetros de la fase viven en el
+// pool para que repartirla no reserve memoria.
+type workerPool struct {
+	numWorkers int
+	blocks     []workChunk // rangos de nodos
+	dangling   []workChunk // posiciones de danglingNodes dentro de cada bloque
+	owned      []workChunk // bloques de cada worker, nil si se toman de next
+	next       atomic.Int64
+	parts      []workerPart
+	wake       []chan poolPhase
+	barrier    sync.WaitGroup
+
+	// En modo determinista cada bloque guarda su propio resultado y se
+	// combinan por pares, así que la suma no depende de qué worker calculó
+	// cada bloque
+	deterministic bool
+	blockSums     []float64
+	blockChanges  []changeAccumulator
+
+	compensated bool   // suma compensada dentro de cada bloque
+	frozen      []bool // nodos congelados por Adaptive, nil sin Adaptive
+
+	// Con el kernel push cada worker reparte el rank de sus orígenes en su
+	// propio búfer (workerPart.buffer) y después cada bloque de destinos
+	// suma los búferes de todos. Los orígenes se reparten aparte porque su
+	// costo son los enlaces salientes.
+	push         bool
+	sources      []workChunk
+	sourcesOwned []workChunk // como owned, para sources
+
+	// Parámetros de la fase en curso
+	followingProb, omega float64
+	t                    teleport
+	c                    *csr
+	p, v                 []float64
+	innerProduct         float64
+	inverseOfSum         float64
+}
+
+// poolPhase es una de las pasadas de una iteración
+type poolPhase int
+
+const (
+	phaseInnerProduct poolPhase = iota // masa de los nodos colgantes
+	phasePower                         // v sin normalizar y su suma
+	phaseGaussSeidel                   // barrido por bloques y su suma
+	phaseNormalize                     // normalización fusionada con el cambio
+	phaseScatter                       // push: reparto de los orígenes en los búferes
+	phaseGather                        // push: suma de los búferes, v y su suma
+)
+
+// workerPart es el resultado parcial y la carga de un worker. Ocupa dos
+// líneas de caché completas para que los workers no escriban en la misma.
+type workerPart struct {
+	sum    float64
+	change changeAccumulator
+	load   WorkerLoad
+	buffer []float64 // acumulador del kernel push, en cero entre iteraciones
+	_      [32]byte
+}
+
+// newWorkerPool arranca numWorkers workers (el 0 es el hilo que llama) sobre
+// los bloques dados: owned[w] es el rango de bloques del worker w, o nil para
+// que los tomen de un contador atómico a medida que terminan. Los nodos
+// colgantes están ordenados por índice, así que cada bloque suma los que caen
+// en su propio rango.
+func newWorkerPool(numWorkers int, blocks, owned []workChunk, options engineOptions, c *csr) *workerPool {
+	pool := &workerPool{
+		numWorkers:    numWorkers,
+		blocks:        blocks,
+		dangling:      make([]workChunk, len(blocks)),
+		owned:         owned,
+		parts:         make([]workerPart, numWorkers),
+		wake:          make([]chan poolPhase, numWorkers),
+		deterministic: options.deterministic,
+		compensated:   options.compensated,
+		frozen:        options.frozen,
+		push:          options.kernel == PushKernel,
+		c:             c,
+	}
+	if pool.push {
+		for w := range pool.parts {
+			pool.parts[w].buffer = make([]float64, c.size())
+		}
+	}
+	if pool.deterministic {
+		pool.blockSums = make([]float64, len(blocks))
+		pool.blockChanges = make([]changeAccumulator, len(blocks))
+	}
+
+	for b, block := range blocks {
+		pool.dangling[b].start = sort.Search(len(c.danglingNodes), func(k int) bool {
+			return int(c.danglingNodes[k]) >= block.start
+		})
+		pool.dangling[b].end = sort.Search(len(c.danglingNodes), func(k int) bool {
+			return int(c.danglingNodes[k]) >= block.end
+		})
+	}
+
+	for w := 1; w < numWorkers; w++ {
+		pool.wake[w] = make(chan poolPhase)
+		go func(worker int) {
+			for phase := range pool.wake[worker] {
+				pool.work(worker, phase)
+				pool.barrier.Done()
+			}
+		}(w)
+	}
+
+	return pool
+}
+
+// run ejecuta phase en todos los workers y vuelve cuando todos terminaron
+func (pool *workerPool) run(phase poolPhase) {
+	pool.next.Store(0)
+	pool.barrier.Add(pool.numWorkers - 1)
+	for w := 1; w < pool.numWorkers; w++ {
+		pool.wake[w] <- phase
+	}
+	pool.work(0, phase)
+	pool.barrier.Wait()
+}
+
+// close detiene los workers
+func (pool *workerPool) close() {
+	for w := 1; w < pool.numWorkers; w++ {
+		close(pool.wake[w])
+	}
+}
+
+// loads devuelve la carga acumulada de cada worker
+func (pool *workerPool) loads() []WorkerLoad {
+	loads := make([]WorkerLoad, pool.numWorkers)
+	for w := range loads {
+		loads[w] = pool.parts[w].load
+	}
+	return loads
+}
+
+// work hace la parte de phase del worker: sus propios bloques, o los que
+// alcance a tomar del contador
+func (pool *workerPool) work(worker int, phase poolPhase) {
+	part := &pool.parts[worker]
+	part.sum = 0
+	part.change = changeAccumulator{}
+	start := time.Now()
+
+	count, owned := len(pool.blocks), pool.owned
+	if phase == phaseScatter {
+		count, owned = len(pool.sources), pool.sourcesOwned
+	}
+
+	if owned != nil {
+		for b := owned[worker].start; b < owned[worker].end; b++ {
+			pool.workBlock(b, phase, part)
+		}
+	} else {
+		for {
+			b := int(pool.next.Add(1)) - 1
+			if b >= count {
+				break
+			}
+			pool.workBlock(b, phase, part)
+		}
+	}
+
+	part.load.Busy += time.Since(start)
+}
+
+func (pool *workerPool) workBlock(b int, phase poolPhase, part *workerPart) {
+	c, t, p, v := pool.c, pool.t, pool.p, pool.v
+	if phase == phaseScatter {
+		sources := pool.sources[b]
+		c.scatter(p, part.buffer, sources.start, sources.end)
+		part.load.Nodes += sources.end - sources.start
+		part.load.Edges += c.outOffsets[sources.end] - c.outOffsets[sources.start]
+		part.load.Blocks++
+		return
+	}
+	block := pool.blocks[b]
+
+	switch phase {
+	case phaseInnerProduct:
+		dangling := c.danglingNodes[pool.dangling[b].start:pool.dangling[b].end]
+		pool.addSum(b, part, danglingMass(p, dangling, pool.compensated))
+
+	case phasePower:
+		followingProb, innerProduct := pool.followingProb, pool.innerProduct
+		vsum := accumulator{compensated: pool.compensated}
+		for i := block.start; i < block.end; i++ {
+			if pool.frozen != nil && pool.frozen[i] {
+				v[i] = p[i]
+			} else {
+				ksum := c.rankIn(i, p, pool.compensated)
+				v[i] = followingProb*(ksum+t.danglingTerm(i, innerProduct, c, p)) + (1.0-followingProb)*t.at(i)
+			}
+			vsum.add(v[i])
+		}
+		pool.addSum(b, part, vsum.value())
+		part.countBlock(c, block)
+
+	case phaseGaussSeidel:
+		// Cada bloque usa sus propios valores nuevos y, para los nodos de
+		// otros bloques, los de la iteración anterior: así no lee datos que
+		// otro worker está escribiendo
+		followingProb, omega, innerProduct := pool.followingProb, pool.omega, pool.innerProduct
+		copy(v[block.start:block.end], p[block.start:block.end])
+		vsum := accumulator{compensated: pool.compensated}
+		for i := block.start; i < block.end; i++ {
+			if pool.frozen != nil && pool.frozen[i] {
+				vsum.add(v[i])
+				continue
+			}
+			ksum := c.inLinksSumBlock(i, p, v, block.start, block.end, pool.compensated)
+			gs := followingProb*(ksum+t.danglingTerm(i, innerProduct, c, v)) + (1.0-followingProb)*t.at(i)
+			v[i] = (1.0-omega)*v[i] + omega*gs
+			vsum.add(v[i])
+		}
+		pool.addSum(b, part, vsum.value())
+		part.countBlock(c, block)
+
+	case phaseGather:
+		// Los búferes quedan en cero para la próxima iteración
+		followingProb, innerProduct := pool.followingProb, pool.innerProduct
+		vsum := accumulator{compensated: pool.compensated}
+		for i := block.start; i < block.end; i++ {
+			ksum := 0.0
+			for w := range pool.parts {
+				buffer := pool.parts[w].buffer
+				ksum += buffer[i]
+				buffer[i] = 0
+			}
+			v[i] = followingProb*(ksum+t.danglingTerm(i, innerProduct, c, p)) + (1.0-followingProb)*t.at(i)
+			vsum.add(v[i])
+		}
+		pool.addSum(b, part, vsum.value())
+
+	case phaseNormalize:
+		acc := normalizeWithChange(p[block.start:block.end], v[block.start:block.end], pool.inverseOfSum)
+		if pool.deterministic {
+			pool.blockChanges[b] = acc
+		} else {
+			part.change.merge(acc)
+		}
+	}
+}
+
+// addSum guarda la suma del bloque b: en el propio bloque en modo
+// determinista, o en el parcial del worker
+func (pool *workerPool) addSum(b int, part *workerPart, sum float64) {
+	if pool.deterministic {
+		pool.blockSums[b] = sum
+	} else {
+		part.sum += sum
+	}
+}
+
+// countBlock suma a la carga del worker un bloque de la fase de cálculo
+func (part *workerPart) countBlock(c *csr, block workChunk) {
+	part.load.Nodes += block.end - block.start
+	part.load.Edges += c.offsets[block.end] - c.offsets[block.start]
+	part.load.Blocks++
+}
+
+// sum reduce las sumas parciales de la última fase
+func (pool *workerPool) sum() float64 {
+	if pool.deterministic {
+		return pairwiseSum(pool.blockSums)
+	}
+
+	total := 0.0
+	for w := range pool.parts {
+		total += pool.parts[w].sum
+	}
+	return total
+}
+
+// iterate ejecuta una iteración completa con la fase de cálculo dada: masa
+// colgante, cálculo de v y normalización junto con el cambio. Son tres
+// barreras por iteración; con el kernel push el cálculo de v se hace en dos
+// fases, reparto y suma, y son cuatro.
+func (pool *workerPool) iterate(compute poolPhase, followingProb, omega float64, t teleport, p, v []float64) Change {
+	pool.followingProb, pool.omega = followingProb, omega
+	pool.t, pool.p, pool.v = t, p, v
+
+	pool.run(phaseInnerProduct)
+	pool.innerProduct = pool.sum()
+
+	if compute == phasePower && pool.push {
+		pool.run(phaseScatter)
+		compute = phaseGather
+	}
+	pool.run(compute)
+	pool.inverseOfSum = 1.0 / pool.sum()
+
+	pool.run(phaseNormalize)
+	var acc changeAccumulator
+	if pool.deterministic {
+		acc = pairwiseMerge(pool.blockChanges)
+	} else {
+		for w := range pool.parts {
+			acc.merge(pool.parts[w].change)
+		}
+	}
+
+	// No retener los vectores del cálculo más allá de la iteración
+	pool.p, pool.v = nil, nil
+	return acc.change(p, v)
+}